@@ -0,0 +1,248 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+// The v1alpha1 kinds are spokes that convert to and from the v1beta1 hub.
+// Conversion is lossless: the few v1alpha1 details that v1beta1 can't
+// represent are kept in an annotation while an object is stored as v1beta1.
+
+// conversionAnnotation holds the v1alpha1Details of an object that was
+// converted to v1beta1, so that they aren't lost when the object is read back
+// as v1alpha1.
+const conversionAnnotation = "databases.digitalocean.com/v1alpha1-conversion"
+
+// v1alpha1Details are the parts of a v1alpha1 object that v1beta1 can't
+// represent.
+type v1alpha1Details struct {
+	// ClusterAPIGroup is the API group of the cluster reference, if it isn't
+	// the databases.digitalocean.com group that v1beta1 implies.
+	ClusterAPIGroup *string `json:"clusterAPIGroup,omitempty"`
+	// NoClusterAPIGroup is true if the cluster reference has no API group.
+	NoClusterAPIGroup bool `json:"noClusterAPIGroup,omitempty"`
+	// EmptyOutput is true if the output settings are set but empty alongside
+	// a secret template, which v1beta1 groups with the output settings.
+	EmptyOutput bool `json:"emptyOutput,omitempty"`
+}
+
+// preserveDetails records details in the conversionAnnotation of obj, or
+// removes the annotation if there are none.
+func preserveDetails(obj metav1.Object, details v1alpha1Details) error {
+	if details == (v1alpha1Details{}) {
+		restoreDetails(obj)
+		return nil
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("encoding v1alpha1 details: %v", err)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[conversionAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// restoreDetails removes the conversionAnnotation from obj and returns the
+// details recorded in it. A malformed annotation is dropped, leaving the
+// defaults that v1beta1 implies.
+func restoreDetails(obj metav1.Object) v1alpha1Details {
+	annotations := obj.GetAnnotations()
+	data, ok := annotations[conversionAnnotation]
+	if !ok {
+		return v1alpha1Details{}
+	}
+	delete(annotations, conversionAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	var details v1alpha1Details
+	if err := json.Unmarshal([]byte(data), &details); err != nil {
+		return v1alpha1Details{}
+	}
+	return details
+}
+
+// convertSlice converts each element of src, keeping nil slices nil.
+func convertSlice[S, D any](src []S, convert func(S) D) []D {
+	if src == nil {
+		return nil
+	}
+	dst := make([]D, len(src))
+	for i := range src {
+		dst[i] = convert(src[i])
+	}
+	return dst
+}
+
+// clusterReferenceToV1beta1 converts a cluster reference to v1beta1, where
+// the API group is implied. Any other API group is recorded in details.
+func clusterReferenceToV1beta1(src ClusterReference, details *v1alpha1Details) v1beta1.ClusterReference {
+	switch {
+	case src.APIGroup == nil:
+		details.NoClusterAPIGroup = true
+	case *src.APIGroup != GroupVersion.Group:
+		details.ClusterAPIGroup = pointer.String(*src.APIGroup)
+	}
+	return v1beta1.ClusterReference{
+		Kind:      src.Kind,
+		Name:      src.Name,
+		Namespace: src.Namespace,
+	}
+}
+
+func clusterReferenceFromV1beta1(src v1beta1.ClusterReference, details v1alpha1Details) ClusterReference {
+	apiGroup := pointer.String(GroupVersion.Group)
+	switch {
+	case details.NoClusterAPIGroup:
+		apiGroup = nil
+	case details.ClusterAPIGroup != nil:
+		apiGroup = pointer.String(*details.ClusterAPIGroup)
+	}
+	return ClusterReference{
+		APIGroup:  apiGroup,
+		Kind:      src.Kind,
+		Name:      src.Name,
+		Namespace: src.Namespace,
+	}
+}
+
+func clusterOutputToV1beta1(src *ClusterOutput) *v1beta1.ClusterOutput {
+	if src == nil {
+		return nil
+	}
+	return &v1beta1.ClusterOutput{
+		ConnectionConfigMapName:        src.ConnectionConfigMapName,
+		PrivateConnectionConfigMapName: src.PrivateConnectionConfigMapName,
+		CredentialsSecretName:          src.CredentialsSecretName,
+		OutputMetadata:                 v1beta1.OutputMetadata(src.OutputMetadata),
+	}
+}
+
+func clusterOutputFromV1beta1(src *v1beta1.ClusterOutput) *ClusterOutput {
+	if src == nil {
+		return nil
+	}
+	return &ClusterOutput{
+		ConnectionConfigMapName:        src.ConnectionConfigMapName,
+		PrivateConnectionConfigMapName: src.PrivateConnectionConfigMapName,
+		CredentialsSecretName:          src.CredentialsSecretName,
+		OutputMetadata:                 OutputMetadata(src.OutputMetadata),
+	}
+}
+
+// databaseClusterOutputToV1beta1 converts a DatabaseCluster's output settings
+// and secret template, which v1beta1 groups together. Empty output settings
+// alongside a secret template are recorded in details.
+func databaseClusterOutputToV1beta1(src *ClusterOutput, secretTemplate map[string]string, details *v1alpha1Details) *v1beta1.DatabaseClusterOutput {
+	if src == nil && len(secretTemplate) == 0 {
+		return nil
+	}
+	dst := &v1beta1.DatabaseClusterOutput{SecretTemplate: secretTemplate}
+	if src != nil {
+		dst.ClusterOutput = *clusterOutputToV1beta1(src)
+		details.EmptyOutput = clusterOutputEmpty(&dst.ClusterOutput) && len(secretTemplate) > 0
+	}
+	return dst
+}
+
+// databaseClusterOutputFromV1beta1 splits a DatabaseCluster's v1beta1 output
+// settings into the output settings and secret template. The output settings
+// are nil if only the secret template is set, unless details say otherwise.
+func databaseClusterOutputFromV1beta1(src *v1beta1.DatabaseClusterOutput, details v1alpha1Details) (*ClusterOutput, map[string]string) {
+	if src == nil {
+		return nil, nil
+	}
+	if clusterOutputEmpty(&src.ClusterOutput) && len(src.SecretTemplate) > 0 && !details.EmptyOutput {
+		return nil, src.SecretTemplate
+	}
+	return clusterOutputFromV1beta1(&src.ClusterOutput), src.SecretTemplate
+}
+
+func userOutputToV1beta1(src *UserOutput) *v1beta1.UserOutput {
+	if src == nil {
+		return nil
+	}
+	return &v1beta1.UserOutput{
+		CredentialsSecretName: src.CredentialsSecretName,
+		OutputMetadata:        v1beta1.OutputMetadata(src.OutputMetadata),
+	}
+}
+
+func userOutputFromV1beta1(src *v1beta1.UserOutput) *UserOutput {
+	if src == nil {
+		return nil
+	}
+	return &UserOutput{
+		CredentialsSecretName: src.CredentialsSecretName,
+		OutputMetadata:        OutputMetadata(src.OutputMetadata),
+	}
+}
+
+// databaseUserOutputToV1beta1 converts a DatabaseUser's output settings and
+// secret template, which v1beta1 groups together. Empty output settings
+// alongside a secret template are recorded in details.
+func databaseUserOutputToV1beta1(src *UserOutput, secretTemplate map[string]string, details *v1alpha1Details) *v1beta1.DatabaseUserOutput {
+	if src == nil && len(secretTemplate) == 0 {
+		return nil
+	}
+	dst := &v1beta1.DatabaseUserOutput{SecretTemplate: secretTemplate}
+	if src != nil {
+		dst.UserOutput = *userOutputToV1beta1(src)
+		details.EmptyOutput = userOutputEmpty(&dst.UserOutput) && len(secretTemplate) > 0
+	}
+	return dst
+}
+
+// databaseUserOutputFromV1beta1 splits a DatabaseUser's v1beta1 output
+// settings into the output settings and secret template. The output settings
+// are nil if only the secret template is set, unless details say otherwise.
+func databaseUserOutputFromV1beta1(src *v1beta1.DatabaseUserOutput, details v1alpha1Details) (*UserOutput, map[string]string) {
+	if src == nil {
+		return nil, nil
+	}
+	if userOutputEmpty(&src.UserOutput) && len(src.SecretTemplate) > 0 && !details.EmptyOutput {
+		return nil, src.SecretTemplate
+	}
+	return userOutputFromV1beta1(&src.UserOutput), src.SecretTemplate
+}
+
+func clusterOutputEmpty(out *v1beta1.ClusterOutput) bool {
+	return out.ConnectionConfigMapName == "" && out.PrivateConnectionConfigMapName == "" &&
+		out.CredentialsSecretName == "" && outputMetadataEmpty(out.OutputMetadata)
+}
+
+func userOutputEmpty(out *v1beta1.UserOutput) bool {
+	return out.CredentialsSecretName == "" && outputMetadataEmpty(out.OutputMetadata)
+}
+
+func outputMetadataEmpty(md v1beta1.OutputMetadata) bool {
+	return len(md.Labels) == 0 && len(md.Annotations) == 0
+}
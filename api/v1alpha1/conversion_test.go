@@ -0,0 +1,140 @@
+package v1alpha1
+
+import (
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/randfill"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+const fuzzIterations = 500
+
+// newConversionFiller returns a filler for random objects of both versions.
+// Its custom functions skip the differences that conversion doesn't need to
+// preserve because they're never stored.
+func newConversionFiller(seed int64) *randfill.Filler {
+	return randfill.NewWithSeed(seed).NilChance(0.3).NumElements(0, 3).Funcs(
+		// The type is set by whoever allocates the converted object.
+		func(tm *metav1.TypeMeta, c randfill.Continue) {
+			*tm = metav1.TypeMeta{}
+		},
+		// randfill leaves optional times nil, since it asks the nil pointer
+		// to fill itself.
+		func(t **metav1.Time, c randfill.Continue) {
+			if c.Bool() {
+				*t = nil
+				return
+			}
+			*t = &metav1.Time{}
+			c.Fill(*t)
+		},
+	)
+}
+
+// convertible is a v1alpha1 kind that converts to and from the hub.
+type convertible[T any] interface {
+	*T
+	conversion.Convertible
+}
+
+// hub is the v1beta1 version of a kind.
+type hub[T any] interface {
+	*T
+	conversion.Hub
+}
+
+// itRoundTrips checks that random objects of a kind survive conversion from
+// v1alpha1 to v1beta1 and back, and from v1beta1 to v1alpha1 and back.
+func itRoundTrips[S any, H any, PS convertible[S], PH hub[H]](kind string) {
+	It("should round-trip "+kind+" from v1alpha1 through v1beta1", func() {
+		filler := newConversionFiller(GinkgoRandomSeed())
+		for i := 0; i < fuzzIterations; i++ {
+			spoke := PS(new(S))
+			filler.Fill(spoke)
+
+			converted := PH(new(H))
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			got := PS(new(S))
+			Expect(got.ConvertFrom(converted)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(got, spoke)).To(BeTrue(), cmp.Diff(spoke, got))
+		}
+	})
+
+	It("should round-trip "+kind+" from v1beta1 through v1alpha1", func() {
+		filler := newConversionFiller(GinkgoRandomSeed())
+		for i := 0; i < fuzzIterations; i++ {
+			hub := PH(new(H))
+			filler.Fill(hub)
+
+			converted := PS(new(S))
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			got := PH(new(H))
+			Expect(converted.ConvertTo(got)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(got, hub)).To(BeTrue(), cmp.Diff(hub, got))
+		}
+	})
+}
+
+var _ = Describe("Conversion", func() {
+	itRoundTrips[DatabaseCluster, v1beta1.DatabaseCluster](DatabaseClusterKind)
+	itRoundTrips[DatabaseClusterReference, v1beta1.DatabaseClusterReference](DatabaseClusterReferenceKind)
+	itRoundTrips[DatabaseUser, v1beta1.DatabaseUser](DatabaseUserKind)
+	itRoundTrips[DatabaseUserReference, v1beta1.DatabaseUserReference](DatabaseUserReferenceKind)
+
+	It("should keep v1alpha1 details while a DatabaseUser is stored as v1beta1", func() {
+		spoke := &DatabaseUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "user"},
+			Spec: DatabaseUserSpec{
+				Cluster: ClusterReference{
+					Kind: DatabaseClusterKind,
+					Name: "cluster",
+				},
+				Output:         &UserOutput{},
+				SecretTemplate: map[string]string{"DSN": "{{ .User }}@{{ .Host }}"},
+			},
+		}
+
+		hub := &v1beta1.DatabaseUser{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).To(HaveKey(conversionAnnotation))
+
+		got := &DatabaseUser{}
+		Expect(got.ConvertFrom(hub)).To(Succeed())
+		Expect(got.Annotations).NotTo(HaveKey(conversionAnnotation))
+		Expect(got.Spec.Cluster.APIGroup).To(BeNil())
+		Expect(got.Spec.Output).To(Equal(&UserOutput{}))
+
+		By("not annotating objects that v1beta1 represents fully", func() {
+			got.Spec.Cluster.APIGroup = pointer.String(GroupVersion.Group)
+			got.Spec.Output = nil
+			Expect(got.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations).NotTo(HaveKey(conversionAnnotation))
+		})
+	})
+
+	It("should group a DatabaseUser's secret template with its output settings", func() {
+		spoke := &DatabaseUser{
+			Spec: DatabaseUserSpec{
+				SecretTemplate: map[string]string{"DSN": "{{ .User }}@{{ .Host }}"},
+			},
+		}
+
+		hub := &v1beta1.DatabaseUser{}
+		Expect(spoke.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Output).NotTo(BeNil())
+		Expect(hub.Spec.Output.SecretTemplate).To(Equal(spoke.Spec.SecretTemplate))
+
+		got := &DatabaseUser{}
+		Expect(got.ConvertFrom(hub)).To(Succeed())
+		Expect(got.Spec.Output).To(BeNil())
+		Expect(got.Spec.SecretTemplate).To(Equal(spoke.Spec.SecretTemplate))
+	})
+})
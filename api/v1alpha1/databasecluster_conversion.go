@@ -0,0 +1,121 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+// ConvertTo converts this DatabaseCluster to the hub version.
+func (src *DatabaseCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DatabaseCluster)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	var details v1alpha1Details
+	dst.Spec = v1beta1.DatabaseClusterSpec{
+		Engine:                             src.Spec.Engine,
+		Name:                               src.Spec.Name,
+		Version:                            src.Spec.Version,
+		NumNodes:                           src.Spec.NumNodes,
+		Size:                               src.Spec.Size,
+		Region:                             src.Spec.Region,
+		StorageSizeMiB:                     src.Spec.StorageSizeMiB,
+		Tags:                               src.Spec.Tags,
+		MaintenanceWindow:                  (*v1beta1.MaintenanceWindow)(src.Spec.MaintenanceWindow),
+		Config:                             src.Spec.Config,
+		DriftPolicy:                        src.Spec.DriftPolicy,
+		MetricsCredentialsRotationInterval: src.Spec.MetricsCredentialsRotationInterval,
+		Output:                             databaseClusterOutputToV1beta1(src.Spec.Output, src.Spec.SecretTemplate, &details),
+		ProviderConfigRef:                  src.Spec.ProviderConfigRef,
+	}
+	if src.Spec.Firewall != nil {
+		dst.Spec.Firewall = &v1beta1.DatabaseFirewall{
+			Rules: convertSlice(src.Spec.Firewall.Rules, func(rule FirewallRule) v1beta1.FirewallRule {
+				return v1beta1.FirewallRule(rule)
+			}),
+		}
+	}
+
+	dst.Status = v1beta1.DatabaseClusterStatus{
+		UUID:                        src.Status.UUID,
+		Status:                      src.Status.Status,
+		CreatedAt:                   src.Status.CreatedAt,
+		ConnectionHash:              src.Status.ConnectionHash,
+		EventsCursor:                (*v1beta1.DatabaseEventsCursor)(src.Status.EventsCursor),
+		MetricsCredentialsRotatedAt: src.Status.MetricsCredentialsRotatedAt,
+		Outputs:                     v1beta1.OutputStatus(src.Status.Outputs),
+		Binding:                     src.Status.Binding,
+		AppliedGeneration:           src.Status.AppliedGeneration,
+		Drift: convertSlice(src.Status.Drift, func(drift FieldDrift) v1beta1.FieldDrift {
+			return v1beta1.FieldDrift(drift)
+		}),
+		Conditions: src.Status.Conditions,
+	}
+	return preserveDetails(dst, details)
+}
+
+// ConvertFrom converts the hub version to this DatabaseCluster.
+func (dst *DatabaseCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DatabaseCluster)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	details := restoreDetails(dst)
+
+	output, secretTemplate := databaseClusterOutputFromV1beta1(src.Spec.Output, details)
+	dst.Spec = DatabaseClusterSpec{
+		Engine:                             src.Spec.Engine,
+		Name:                               src.Spec.Name,
+		Version:                            src.Spec.Version,
+		NumNodes:                           src.Spec.NumNodes,
+		Size:                               src.Spec.Size,
+		Region:                             src.Spec.Region,
+		StorageSizeMiB:                     src.Spec.StorageSizeMiB,
+		Tags:                               src.Spec.Tags,
+		MaintenanceWindow:                  (*MaintenanceWindow)(src.Spec.MaintenanceWindow),
+		Config:                             src.Spec.Config,
+		DriftPolicy:                        src.Spec.DriftPolicy,
+		MetricsCredentialsRotationInterval: src.Spec.MetricsCredentialsRotationInterval,
+		SecretTemplate:                     secretTemplate,
+		Output:                             output,
+		ProviderConfigRef:                  src.Spec.ProviderConfigRef,
+	}
+	if src.Spec.Firewall != nil {
+		dst.Spec.Firewall = &DatabaseFirewall{
+			Rules: convertSlice(src.Spec.Firewall.Rules, func(rule v1beta1.FirewallRule) FirewallRule {
+				return FirewallRule(rule)
+			}),
+		}
+	}
+
+	dst.Status = DatabaseClusterStatus{
+		UUID:                        src.Status.UUID,
+		Status:                      src.Status.Status,
+		CreatedAt:                   src.Status.CreatedAt,
+		ConnectionHash:              src.Status.ConnectionHash,
+		EventsCursor:                (*DatabaseEventsCursor)(src.Status.EventsCursor),
+		MetricsCredentialsRotatedAt: src.Status.MetricsCredentialsRotatedAt,
+		Outputs:                     OutputStatus(src.Status.Outputs),
+		Binding:                     src.Status.Binding,
+		AppliedGeneration:           src.Status.AppliedGeneration,
+		Drift: convertSlice(src.Status.Drift, func(drift v1beta1.FieldDrift) FieldDrift {
+			return FieldDrift(drift)
+		}),
+		Conditions: src.Status.Conditions,
+	}
+	return nil
+}
@@ -19,36 +19,146 @@ package v1alpha1
 import (
 	"github.com/digitalocean/do-operator/extgodo"
 	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DefaultsAnnotation is set on a DatabaseCluster by the defaulting webhook to
+// a JSON object mapping the spec fields it defaulted to the values it chose.
+const DefaultsAnnotation = "databases.digitalocean.com/defaults"
+
 // DatabaseClusterSpec defines the desired state of DatabaseCluster
 type DatabaseClusterSpec struct {
 	// Engine is the database engine to use.
 	Engine string `json:"engine"`
-	// Name is the name of the database cluster.
-	Name string `json:"name"`
-	// Version is the DB version to use.
-	Version string `json:"version"`
-	// NumNodes is the number of nodes in the database cluster.
-	NumNodes int64 `json:"numNodes"`
+	// Name is the name of the database cluster. Defaults to the name of the
+	// DatabaseCluster object.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Version is the DB version to use. Defaults to the newest version
+	// available for the engine.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// NumNodes is the number of nodes in the database cluster. Defaults to
+	// the smallest number of nodes available for the engine.
+	// +optional
+	NumNodes int64 `json:"numNodes,omitempty"`
 	// Size is the slug of the node size to use.
 	Size string `json:"size"`
-	// Region is the slug of the DO region for the cluster.
-	Region string `json:"region"`
+	// Region is the slug of the DO region for the cluster. Defaults to the
+	// operator's --default-region flag, if set.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// StorageSizeMiB, if set, is the cluster's disk size in MiB. Defaults to
+	// the size included with the node size.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StorageSizeMiB *int64 `json:"storageSizeMiB,omitempty"`
+	// Firewall, if set, restricts inbound connections to the cluster to the
+	// given rules. The cluster's rules are left alone if it's unset.
+	// +optional
+	Firewall *DatabaseFirewall `json:"firewall,omitempty"`
+	// Tags, if set, are the cluster's tags. The cluster's tags are left alone
+	// if it's empty.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// MaintenanceWindow, if set, is when maintenance updates are applied to
+	// the cluster. The cluster's window is left alone if it's unset.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// Config sets engine-specific configuration options, using the names
+	// from the DigitalOcean API, e.g. "sql_mode" for MySQL. Options that
+	// aren't set are left alone.
+	// +optional
+	Config map[string]apiextensionsv1.JSON `json:"config,omitempty"`
+	// DriftPolicy is what to do when the cluster's settings in DigitalOcean
+	// no longer match the spec: Observe only reports the drift, while Enforce
+	// also changes the cluster back. Changes to the spec are applied under
+	// either policy. Defaults to the operator's --drift-policy flag, which
+	// defaults to Observe.
+	// +kubebuilder:validation:Enum=Observe;Enforce
+	// +optional
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+	// MetricsCredentialsRotationInterval, if set, is how often the credentials
+	// for the database metrics endpoints are rotated. Note that the metrics
+	// credentials are shared by all database clusters in the DigitalOcean
+	// account, so rotating them affects every cluster's metrics Secret. The
+	// operator rotates them at most once per interval across the clusters
+	// using the same provider config, and then updates every metrics Secret.
+	MetricsCredentialsRotationInterval *metav1.Duration `json:"metricsCredentialsRotationInterval,omitempty"`
+	// SecretTemplate adds keys to the default user's credentials Secret. Each
+	// value is a Go template over the connection fields .User, .Password,
+	// .Host, .Port, .Database, .SSLMode and .CA. A templated key replaces the
+	// default key of the same name.
+	// +optional
+	SecretTemplate map[string]string `json:"secretTemplate,omitempty"`
+	// Output configures the names and metadata of the generated ConfigMaps
+	// and Secret.
+	// +optional
+	Output *ClusterOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// DatabaseFirewall is the set of inbound sources allowed to connect to a
+// database cluster.
+type DatabaseFirewall struct {
+	// Rules are the allowed sources. An empty list allows every source.
+	// +optional
+	Rules []FirewallRule `json:"rules,omitempty"`
+}
+
+// FirewallRule allows inbound connections from one source.
+type FirewallRule struct {
+	// Type is the type of the source.
+	// +kubebuilder:validation:Enum=ip_addr;droplet;k8s;tag;app
+	Type string `json:"type"`
+	// Value identifies the source: an IP address or CIDR, a Droplet ID, a
+	// Kubernetes cluster UUID, a tag or an App Platform app UUID.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// MaintenanceWindow is the weekly window in which maintenance updates are
+// applied.
+type MaintenanceWindow struct {
+	// Day is the day of the week.
+	// +kubebuilder:validation:Enum=monday;tuesday;wednesday;thursday;friday;saturday;sunday
+	Day string `json:"day"`
+	// Hour is the start of the window in UTC, as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Hour string `json:"hour"`
 }
 
 // ToGodoCreateRequest returns a create request for a database that will fulfill
 // the DatabaseClusterSpec.
 func (spec *DatabaseClusterSpec) ToGodoCreateRequest() *godo.DatabaseCreateRequest {
-	return &godo.DatabaseCreateRequest{
+	req := &godo.DatabaseCreateRequest{
 		EngineSlug: spec.Engine,
 		Name:       spec.Name,
 		Version:    spec.Version,
 		SizeSlug:   spec.Size,
 		Region:     spec.Region,
 		NumNodes:   int(spec.NumNodes),
+		Tags:       spec.Tags,
+	}
+	if spec.StorageSizeMiB != nil {
+		req.StorageSizeMib = uint64(*spec.StorageSizeMiB)
+	}
+	if spec.Firewall != nil {
+		for _, rule := range spec.Firewall.Rules {
+			req.Rules = append(req.Rules, &godo.DatabaseCreateFirewallRule{
+				Type:  rule.Type,
+				Value: rule.Value,
+			})
+		}
 	}
+	return req
 }
 
 // ToGodoValidateCreateRequest returns a validation request for a database that
@@ -69,10 +179,52 @@ type DatabaseClusterStatus struct {
 	Status string `json:"status,omitempty"`
 	// CreatedAt is the time at which the database cluster was created.
 	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// ConnectionHash is a hash of the database cluster's connection details,
+	// which changes when its hosts, ports or default database do.
+	ConnectionHash string `json:"connectionHash,omitempty"`
+	// EventsCursor records the database cluster's events that have been
+	// emitted as Kubernetes Events.
+	EventsCursor *DatabaseEventsCursor `json:"eventsCursor,omitempty"`
+	// MetricsCredentialsRotatedAt is the time at which the metrics credentials
+	// were last rotated, by this cluster or another sharing them, or when
+	// rotation was enabled if they haven't been rotated yet.
+	MetricsCredentialsRotatedAt *metav1.Time `json:"metricsCredentialsRotatedAt,omitempty"`
+	// Outputs records the names of the generated ConfigMaps and Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the default credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// AppliedGeneration is the generation of the spec that was last applied
+	// to the cluster in DigitalOcean. Spec changes are applied whatever the
+	// drift policy, which only governs changes made outside the operator.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+	// Drift lists the settings of the cluster in DigitalOcean that don't
+	// match the spec, as of the last reconcile.
+	// +optional
+	Drift []FieldDrift `json:"drift,omitempty"`
+	// Conditions describe the state of the cluster. The Ready condition is
+	// true while the cluster is online and the last reconcile succeeded, and
+	// the Drifted condition is true while Drift isn't empty.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// DatabaseEventsCursor records the DigitalOcean database events, such as
+// maintenance and failover events, that have already been emitted as
+// Kubernetes Events, so that each is emitted only once.
+type DatabaseEventsCursor struct {
+	// LastEventTime is the creation time of the most recent event emitted.
+	LastEventTime metav1.Time `json:"lastEventTime"`
+	// LastEventIDs are the IDs of the emitted events created at LastEventTime.
+	LastEventIDs []string `json:"lastEventIDs,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
 
 // DatabaseCluster is the Schema for the databaseclusters API
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
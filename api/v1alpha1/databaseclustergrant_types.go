@@ -0,0 +1,128 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterReference is a reference to a DatabaseCluster or
+// DatabaseClusterReference, which may be in another namespace.
+// +structType=atomic
+type ClusterReference struct {
+	// APIGroup is the group for the resource being referenced. It must be
+	// databases.digitalocean.com.
+	// +optional
+	APIGroup *string `json:"apiGroup"`
+	// Kind is the type of resource being referenced, DatabaseCluster or
+	// DatabaseClusterReference.
+	Kind string `json:"kind"`
+	// Name is the name of the resource being referenced.
+	Name string `json:"name"`
+	// Namespace is the namespace of the resource being referenced. Defaults
+	// to the namespace of the referencing object. Referencing a resource in
+	// another namespace requires a DatabaseClusterGrant in that namespace
+	// that allows it.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NamespaceFor returns the namespace of the referenced resource when the
+// reference is made from an object in namespace.
+func (r *ClusterReference) NamespaceFor(namespace string) string {
+	if r.Namespace == "" {
+		return namespace
+	}
+	return r.Namespace
+}
+
+// GrantedCluster identifies a DatabaseCluster or DatabaseClusterReference
+// that a DatabaseClusterGrant allows references to.
+type GrantedCluster struct {
+	// Kind is the kind of the resource, DatabaseCluster or
+	// DatabaseClusterReference.
+	// +kubebuilder:validation:Enum=DatabaseCluster;DatabaseClusterReference
+	Kind string `json:"kind"`
+	// Name is the name of the resource. Defaults to all resources of the
+	// kind in the grant's namespace.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// DatabaseClusterGrantSpec defines the desired state of DatabaseClusterGrant
+type DatabaseClusterGrantSpec struct {
+	// Namespaces are the namespaces whose DatabaseUsers and
+	// DatabaseUserReferences may reference clusters in the grant's namespace.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+	// Clusters limits the grant to the listed DatabaseClusters and
+	// DatabaseClusterReferences. Defaults to all of them in the grant's
+	// namespace.
+	// +optional
+	Clusters []GrantedCluster `json:"clusters,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DatabaseClusterGrant is the Schema for the databaseclustergrants API. It
+// allows DatabaseUsers and DatabaseUserReferences in other namespaces to
+// reference DatabaseClusters and DatabaseClusterReferences in its namespace.
+type DatabaseClusterGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatabaseClusterGrantSpec `json:"spec,omitempty"`
+}
+
+// Allows returns whether the grant allows objects in namespace to reference
+// the cluster resource of the given kind and name in the grant's namespace.
+func (g *DatabaseClusterGrant) Allows(namespace, kind, name string) bool {
+	if !slices.Contains(g.Spec.Namespaces, namespace) {
+		return false
+	}
+	if len(g.Spec.Clusters) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(g.Spec.Clusters, func(cluster GrantedCluster) bool {
+		return strings.EqualFold(cluster.Kind, kind) && (cluster.Name == "" || cluster.Name == name)
+	})
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseClusterGrantList contains a list of DatabaseClusterGrant
+type DatabaseClusterGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseClusterGrant `json:"items"`
+}
+
+// Allows returns whether any grant in the list allows objects in namespace to
+// reference the cluster resource of the given kind and name.
+func (l *DatabaseClusterGrantList) Allows(namespace, kind, name string) bool {
+	return slices.ContainsFunc(l.Items, func(grant DatabaseClusterGrant) bool {
+		return grant.Allows(namespace, kind, name)
+	})
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseClusterGrant{}, &DatabaseClusterGrantList{})
+}
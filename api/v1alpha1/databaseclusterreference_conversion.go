@@ -0,0 +1,79 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+// ConvertTo converts this DatabaseClusterReference to the hub version.
+func (src *DatabaseClusterReference) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DatabaseClusterReference)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec = v1beta1.DatabaseClusterReferenceSpec{
+		UUID:              src.Spec.UUID,
+		Output:            clusterOutputToV1beta1(src.Spec.Output),
+		ProviderConfigRef: src.Spec.ProviderConfigRef,
+	}
+	dst.Status = v1beta1.DatabaseClusterReferenceStatus{
+		Engine:         src.Status.Engine,
+		Name:           src.Status.Name,
+		Version:        src.Status.Version,
+		NumNodes:       src.Status.NumNodes,
+		Size:           src.Status.Size,
+		Region:         src.Status.Region,
+		Status:         src.Status.Status,
+		CreatedAt:      src.Status.CreatedAt,
+		ConnectionHash: src.Status.ConnectionHash,
+		EventsCursor:   (*v1beta1.DatabaseEventsCursor)(src.Status.EventsCursor),
+		Outputs:        v1beta1.OutputStatus(src.Status.Outputs),
+		Binding:        src.Status.Binding,
+		Conditions:     src.Status.Conditions,
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this DatabaseClusterReference.
+func (dst *DatabaseClusterReference) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DatabaseClusterReference)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	dst.Spec = DatabaseClusterReferenceSpec{
+		UUID:              src.Spec.UUID,
+		Output:            clusterOutputFromV1beta1(src.Spec.Output),
+		ProviderConfigRef: src.Spec.ProviderConfigRef,
+	}
+	dst.Status = DatabaseClusterReferenceStatus{
+		Engine:         src.Status.Engine,
+		Name:           src.Status.Name,
+		Version:        src.Status.Version,
+		NumNodes:       src.Status.NumNodes,
+		Size:           src.Status.Size,
+		Region:         src.Status.Region,
+		Status:         src.Status.Status,
+		CreatedAt:      src.Status.CreatedAt,
+		ConnectionHash: src.Status.ConnectionHash,
+		EventsCursor:   (*DatabaseEventsCursor)(src.Status.EventsCursor),
+		Outputs:        OutputStatus(src.Status.Outputs),
+		Binding:        src.Status.Binding,
+		Conditions:     src.Status.Conditions,
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,6 +25,16 @@ import (
 type DatabaseClusterReferenceSpec struct {
 	// UUID is the UUID of an existing database.
 	UUID string `json:"uuid"`
+	// Output configures the names and metadata of the generated ConfigMaps
+	// and Secret.
+	// +optional
+	Output *ClusterOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
 }
 
 // DatabaseClusterReferenceStatus defines the observed state of DatabaseClusterReference
@@ -44,10 +55,29 @@ type DatabaseClusterReferenceStatus struct {
 	Status string `json:"status,omitempty"`
 	// CreatedAt is the time at which the database cluster was created.
 	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// ConnectionHash is a hash of the database cluster's connection details,
+	// which changes when its hosts, ports or default database do.
+	ConnectionHash string `json:"connectionHash,omitempty"`
+	// EventsCursor records the database cluster's events that have been
+	// emitted as Kubernetes Events.
+	EventsCursor *DatabaseEventsCursor `json:"eventsCursor,omitempty"`
+	// Outputs records the names of the generated ConfigMaps and Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the default credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Conditions describe the state of the referenced cluster. The Ready
+	// condition is true while the cluster is online and the last reconcile
+	// succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Engine",type=string,JSONPath=`.status.engine`
 // +kubebuilder:printcolumn:name="Cluster name",type=string,JSONPath=`.status.name`
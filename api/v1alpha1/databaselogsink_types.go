@@ -0,0 +1,186 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LogsinkTypeRsyslog is the sink type of logsinks that ship logs to an
+	// rsyslog server.
+	LogsinkTypeRsyslog = "rsyslog"
+	// LogsinkTypeElasticsearch is the sink type of logsinks that ship logs to
+	// an Elasticsearch cluster.
+	LogsinkTypeElasticsearch = "elasticsearch"
+	// LogsinkTypeOpensearch is the sink type of logsinks that ship logs to an
+	// OpenSearch cluster.
+	LogsinkTypeOpensearch = "opensearch"
+	// LogsinkTypeDatadog is the sink type of logsinks that ship logs to
+	// Datadog.
+	LogsinkTypeDatadog = "datadog"
+)
+
+// DatabaseLogsinkSpec defines the desired state of DatabaseLogsink. Exactly one
+// of Rsyslog, Elasticsearch, Opensearch and Datadog must be set.
+type DatabaseLogsinkSpec struct {
+	// Cluster is a reference to the DatabaseCluster or DatabaseClusterReference
+	// that represents the database cluster whose logs will be shipped.
+	Cluster corev1.TypedLocalObjectReference `json:"databaseCluster"`
+	// Name is the name of the logsink.
+	Name string `json:"name"`
+	// CredentialsSecretRef references a Secret in the same namespace that holds
+	// the logsink's credentials. The keys used depend on the sink type:
+	// rsyslog sinks read the optional "ca", "cert" and "key" keys,
+	// Elasticsearch and OpenSearch sinks read the "url" key (including any
+	// credentials) and the optional "ca" key, and Datadog sinks read the
+	// "apiKey" key.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	// Rsyslog configures a logsink that ships logs to an rsyslog server.
+	// +optional
+	Rsyslog *RsyslogLogsinkConfig `json:"rsyslog,omitempty"`
+	// Elasticsearch configures a logsink that ships logs to an Elasticsearch
+	// cluster.
+	// +optional
+	Elasticsearch *ElasticsearchLogsinkConfig `json:"elasticsearch,omitempty"`
+	// Opensearch configures a logsink that ships logs to an OpenSearch
+	// cluster.
+	// +optional
+	Opensearch *ElasticsearchLogsinkConfig `json:"opensearch,omitempty"`
+	// Datadog configures a logsink that ships logs to Datadog.
+	// +optional
+	Datadog *DatadogLogsinkConfig `json:"datadog,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// RsyslogLogsinkConfig configures a logsink that ships logs to an rsyslog
+// server.
+type RsyslogLogsinkConfig struct {
+	// Server is the hostname or IP address of the rsyslog server.
+	Server string `json:"server"`
+	// Port is the port of the rsyslog server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// TLS enables TLS when connecting to the rsyslog server.
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+	// Format is the message format.
+	// +kubebuilder:validation:Enum=rfc5424;rfc3164;custom
+	Format string `json:"format"`
+	// Logline is the log line template used with the custom format.
+	// +optional
+	Logline string `json:"logline,omitempty"`
+	// SD is the structured data block used with the rfc5424 format.
+	// +optional
+	SD string `json:"sd,omitempty"`
+}
+
+// ElasticsearchLogsinkConfig configures a logsink that ships logs to an
+// Elasticsearch or OpenSearch cluster. The connection URL is read from the
+// credentials Secret.
+type ElasticsearchLogsinkConfig struct {
+	// IndexPrefix is the prefix of the indexes logs are written to.
+	IndexPrefix string `json:"indexPrefix"`
+	// IndexDaysMax is the maximum number of days of logs to keep.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IndexDaysMax int32 `json:"indexDaysMax,omitempty"`
+	// TimeoutSeconds is the timeout for requests to the cluster.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// DatadogLogsinkConfig configures a logsink that ships logs to Datadog. The
+// API key is read from the credentials Secret.
+type DatadogLogsinkConfig struct {
+	// Site is the Datadog intake site, e.g., http-intake.logs.datadoghq.com.
+	Site string `json:"site"`
+}
+
+// SinkType returns the DigitalOcean sink type for the configuration set in
+// the spec, or an empty string if none is set.
+func (spec *DatabaseLogsinkSpec) SinkType() string {
+	switch {
+	case spec.Rsyslog != nil:
+		return LogsinkTypeRsyslog
+	case spec.Elasticsearch != nil:
+		return LogsinkTypeElasticsearch
+	case spec.Opensearch != nil:
+		return LogsinkTypeOpensearch
+	case spec.Datadog != nil:
+		return LogsinkTypeDatadog
+	}
+	return ""
+}
+
+// DatabaseLogsinkStatus defines the observed state of DatabaseLogsink
+type DatabaseLogsinkStatus struct {
+	// ClusterUUID is the UUID of the cluster this logsink belongs to. We keep
+	// this in the status so that we can manage the logsink even if the
+	// referenced Cluster CR is deleted.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// SinkID is the ID of the logsink.
+	SinkID string `json:"sinkID,omitempty"`
+	// Creating is set while the operator creates the logsink. A logsink with
+	// the same name is only adopted while it's set, in case a previous
+	// reconcile created the logsink without recording its ID.
+	Creating bool `json:"creating,omitempty"`
+	// Type is the sink type of the logsink.
+	Type string `json:"type,omitempty"`
+	// ConfigHash is a hash of the last configuration, including credentials,
+	// applied to the logsink. It is used to detect changes that need to be
+	// pushed to the API, since credentials are not returned by it.
+	ConfigHash string `json:"configHash,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Sink name",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.status.type`
+//+kubebuilder:printcolumn:name="Sink ID",type=string,JSONPath=`.status.sinkID`
+
+// DatabaseLogsink is the Schema for the databaselogsinks API
+type DatabaseLogsink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseLogsinkSpec   `json:"spec,omitempty"`
+	Status DatabaseLogsinkStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseLogsinkList contains a list of DatabaseLogsink
+type DatabaseLogsinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseLogsink `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseLogsink{}, &DatabaseLogsinkList{})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OnlineMigrationStatusDone is the status of an online migration that has
+	// completed.
+	OnlineMigrationStatusDone = "done"
+	// OnlineMigrationStatusCanceled is the status of an online migration that
+	// was stopped before completing.
+	OnlineMigrationStatusCanceled = "canceled"
+	// OnlineMigrationStatusError is the status of an online migration that has
+	// failed.
+	OnlineMigrationStatusError = "error"
+)
+
+// DatabaseOnlineMigrationSpec defines the desired state of DatabaseOnlineMigration
+type DatabaseOnlineMigrationSpec struct {
+	// Cluster is a reference to the DatabaseCluster or DatabaseClusterReference
+	// that represents the database cluster data will be migrated to.
+	Cluster corev1.TypedLocalObjectReference `json:"databaseCluster"`
+	// Source is the database data will be migrated from.
+	Source OnlineMigrationSource `json:"source"`
+	// DisableSSL disables SSL when connecting to the source database.
+	// +optional
+	DisableSSL bool `json:"disableSSL,omitempty"`
+	// IgnoreDBs is a list of databases on the source that should not be
+	// migrated.
+	// +optional
+	IgnoreDBs []string `json:"ignoreDBs,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// OnlineMigrationSource describes the database an online migration copies
+// data from.
+type OnlineMigrationSource struct {
+	// Host is the hostname or IP address of the source database.
+	Host string `json:"host"`
+	// Port is the port of the source database.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// DatabaseName is the name of the database to connect to on the source.
+	// +optional
+	DatabaseName string `json:"databaseName,omitempty"`
+	// CredentialsSecretRef references a Secret in the same namespace that holds
+	// the credentials for the source database in its "username" and
+	// "password" keys.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+}
+
+// DatabaseOnlineMigrationStatus defines the observed state of DatabaseOnlineMigration
+type DatabaseOnlineMigrationStatus struct {
+	// ClusterUUID is the UUID of the cluster data is migrated to. We keep this
+	// in the status so that we can manage the migration even if the
+	// referenced Cluster CR is deleted.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// MigrationID is the ID of the online migration.
+	MigrationID string `json:"migrationID,omitempty"`
+	// Status is the status of the online migration, e.g., syncing or done.
+	Status string `json:"status,omitempty"`
+	// CreatedAt is the time at which the online migration was started.
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// IsTerminalOnlineMigrationStatus returns true if an online migration with the
+// given status has finished, either successfully or not.
+func IsTerminalOnlineMigrationStatus(status string) bool {
+	switch status {
+	case OnlineMigrationStatusDone, OnlineMigrationStatusCanceled, OnlineMigrationStatusError:
+		return true
+	}
+	return false
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source.host`
+//+kubebuilder:printcolumn:name="Migration ID",type=string,JSONPath=`.status.migrationID`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+
+// DatabaseOnlineMigration is the Schema for the databaseonlinemigrations API
+type DatabaseOnlineMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseOnlineMigrationSpec   `json:"spec,omitempty"`
+	Status DatabaseOnlineMigrationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseOnlineMigrationList contains a list of DatabaseOnlineMigration
+type DatabaseOnlineMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseOnlineMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseOnlineMigration{}, &DatabaseOnlineMigrationList{})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+// ConvertTo converts this DatabaseUser to the hub version.
+func (src *DatabaseUser) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DatabaseUser)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	var details v1alpha1Details
+	dst.Spec = v1beta1.DatabaseUserSpec{
+		ClusterRef:      clusterReferenceToV1beta1(src.Spec.Cluster, &details),
+		Username:        src.Spec.Username,
+		Database:        src.Spec.Database,
+		MySQLAuthPlugin: src.Spec.MySQLAuthPlugin,
+		ACL: convertSlice(src.Spec.ACL, func(acl KafkaACL) v1beta1.KafkaACL {
+			return v1beta1.KafkaACL(acl)
+		}),
+		MongoDB:               (*v1beta1.MongoDBUserSettings)(src.Spec.MongoDB),
+		ResetPasswordWhenLost: src.Spec.ResetPasswordWhenLost,
+		Output:                databaseUserOutputToV1beta1(src.Spec.Output, src.Spec.SecretTemplate, &details),
+		ProviderConfigRef:     src.Spec.ProviderConfigRef,
+	}
+	if src.Spec.OpenSearch != nil {
+		dst.Spec.OpenSearch = &v1beta1.OpenSearchUserSettings{
+			ACL: convertSlice(src.Spec.OpenSearch.ACL, func(acl OpenSearchACL) v1beta1.OpenSearchACL {
+				return v1beta1.OpenSearchACL(acl)
+			}),
+		}
+	}
+	if src.Spec.PasswordRotation != nil {
+		dst.Spec.PasswordRotation = &v1beta1.PasswordRotation{
+			Interval: src.Spec.PasswordRotation.Interval,
+			RolloutTargets: convertSlice(src.Spec.PasswordRotation.RolloutTargets, func(target RolloutTarget) v1beta1.RolloutTarget {
+				return v1beta1.RolloutTarget(target)
+			}),
+		}
+	}
+
+	dst.Status = v1beta1.DatabaseUserStatus{
+		ClusterUUID:         src.Status.ClusterUUID,
+		Role:                src.Status.Role,
+		LastRotatedAt:       src.Status.LastRotatedAt,
+		LastRotationRequest: src.Status.LastRotationRequest,
+		LostPasswordResetAt: src.Status.LostPasswordResetAt,
+		CredentialsHash:     src.Status.CredentialsHash,
+		Outputs:             v1beta1.OutputStatus(src.Status.Outputs),
+		Binding:             src.Status.Binding,
+		Conditions:          src.Status.Conditions,
+	}
+	return preserveDetails(dst, details)
+}
+
+// ConvertFrom converts the hub version to this DatabaseUser.
+func (dst *DatabaseUser) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DatabaseUser)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	details := restoreDetails(dst)
+
+	output, secretTemplate := databaseUserOutputFromV1beta1(src.Spec.Output, details)
+	dst.Spec = DatabaseUserSpec{
+		Cluster:         clusterReferenceFromV1beta1(src.Spec.ClusterRef, details),
+		Username:        src.Spec.Username,
+		Database:        src.Spec.Database,
+		MySQLAuthPlugin: src.Spec.MySQLAuthPlugin,
+		ACL: convertSlice(src.Spec.ACL, func(acl v1beta1.KafkaACL) KafkaACL {
+			return KafkaACL(acl)
+		}),
+		MongoDB:               (*MongoDBUserSettings)(src.Spec.MongoDB),
+		ResetPasswordWhenLost: src.Spec.ResetPasswordWhenLost,
+		SecretTemplate:        secretTemplate,
+		Output:                output,
+		ProviderConfigRef:     src.Spec.ProviderConfigRef,
+	}
+	if src.Spec.OpenSearch != nil {
+		dst.Spec.OpenSearch = &OpenSearchUserSettings{
+			ACL: convertSlice(src.Spec.OpenSearch.ACL, func(acl v1beta1.OpenSearchACL) OpenSearchACL {
+				return OpenSearchACL(acl)
+			}),
+		}
+	}
+	if src.Spec.PasswordRotation != nil {
+		dst.Spec.PasswordRotation = &PasswordRotation{
+			Interval: src.Spec.PasswordRotation.Interval,
+			RolloutTargets: convertSlice(src.Spec.PasswordRotation.RolloutTargets, func(target v1beta1.RolloutTarget) RolloutTarget {
+				return RolloutTarget(target)
+			}),
+		}
+	}
+
+	dst.Status = DatabaseUserStatus{
+		ClusterUUID:         src.Status.ClusterUUID,
+		Role:                src.Status.Role,
+		LastRotatedAt:       src.Status.LastRotatedAt,
+		LastRotationRequest: src.Status.LastRotationRequest,
+		LostPasswordResetAt: src.Status.LostPasswordResetAt,
+		CredentialsHash:     src.Status.CredentialsHash,
+		Outputs:             OutputStatus(src.Status.Outputs),
+		Binding:             src.Status.Binding,
+		Conditions:          src.Status.Conditions,
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/digitalocean/do-operator/api/v1beta1"
+)
+
+// ConvertTo converts this DatabaseUserReference to the hub version.
+func (src *DatabaseUserReference) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.DatabaseUserReference)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	var details v1alpha1Details
+	dst.Spec = v1beta1.DatabaseUserReferenceSpec{
+		ClusterRef:        clusterReferenceToV1beta1(src.Spec.Cluster, &details),
+		Username:          src.Spec.Username,
+		Database:          src.Spec.Database,
+		Output:            userOutputToV1beta1(src.Spec.Output),
+		ProviderConfigRef: src.Spec.ProviderConfigRef,
+	}
+	dst.Status = v1beta1.DatabaseUserReferenceStatus{
+		ClusterUUID: src.Status.ClusterUUID,
+		Role:        src.Status.Role,
+		Outputs:     v1beta1.OutputStatus(src.Status.Outputs),
+		Binding:     src.Status.Binding,
+		Conditions:  src.Status.Conditions,
+	}
+	return preserveDetails(dst, details)
+}
+
+// ConvertFrom converts the hub version to this DatabaseUserReference.
+func (dst *DatabaseUserReference) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.DatabaseUserReference)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	details := restoreDetails(dst)
+
+	dst.Spec = DatabaseUserReferenceSpec{
+		Cluster:           clusterReferenceFromV1beta1(src.Spec.ClusterRef, details),
+		Username:          src.Spec.Username,
+		Database:          src.Spec.Database,
+		Output:            userOutputFromV1beta1(src.Spec.Output),
+		ProviderConfigRef: src.Spec.ProviderConfigRef,
+	}
+	dst.Status = DatabaseUserReferenceStatus{
+		ClusterUUID: src.Status.ClusterUUID,
+		Role:        src.Status.Role,
+		Outputs:     OutputStatus(src.Status.Outputs),
+		Binding:     src.Status.Binding,
+		Conditions:  src.Status.Conditions,
+	}
+	return nil
+}
@@ -25,9 +25,25 @@ import (
 type DatabaseUserReferenceSpec struct {
 	// Cluster is a reference to the DatabaseCluster or DatabaseClusterReference
 	// that represents the database cluster in which the user exists.
-	Cluster corev1.TypedLocalObjectReference `json:"databaseCluster"`
+	// It may be in another namespace if a DatabaseClusterGrant there allows it.
+	Cluster ClusterReference `json:"databaseCluster"`
 	// Username is the username of the referenced user.
 	Username string `json:"username"`
+	// Database is the logical database to use in the connection details in
+	// the user's credentials Secret. Defaults to the cluster's default
+	// database.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// Output configures the name and metadata of the generated credentials
+	// Secret.
+	// +optional
+	Output *UserOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
 }
 
 // DatabaseUserReferenceStatus defines the observed state of DatabaseUserReference
@@ -38,10 +54,22 @@ type DatabaseUserReferenceStatus struct {
 	ClusterUUID string `json:"clusterUUID,omitempty"`
 	// Role is the user's role.
 	Role string `json:"role,omitempty"`
+	// Outputs records the names of the generated credentials Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Conditions describe the state of the user reference. The Ready
+	// condition is true once its credentials Secret is up to date.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 //+kubebuilder:printcolumn:name="Cluster name",type=string,JSONPath=`.spec.databaseCluster.name`
 //+kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.spec.username`
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultProviderConfigName is the name of the DigitalOceanProviderConfig
+	// used by objects in its namespace that don't name a provider config.
+	DefaultProviderConfigName = "default"
+	// DefaultProviderConfigTokenKey is the default key of the API token in a
+	// provider config's credentials Secret.
+	DefaultProviderConfigTokenKey = "access-token"
+)
+
+// ProviderCredentialsSecretReference identifies the key of a Secret holding a
+// DigitalOcean API token.
+type ProviderCredentialsSecretReference struct {
+	// Name is the name of the Secret, in the provider config's namespace.
+	Name string `json:"name"`
+	// Key is the key of the API token in the Secret. Defaults to
+	// access-token.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DigitalOceanProviderConfigSpec defines the desired state of DigitalOceanProviderConfig
+type DigitalOceanProviderConfigSpec struct {
+	// CredentialsSecretRef references the Secret holding the API token for
+	// the DigitalOcean team to manage resources in.
+	CredentialsSecretRef ProviderCredentialsSecretReference `json:"credentialsSecretRef"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.credentialsSecretRef.name`
+
+// DigitalOceanProviderConfig is the Schema for the digitaloceanproviderconfigs
+// API. It configures the DigitalOcean API token used for objects that
+// reference it, so that one operator can manage resources in several teams.
+type DigitalOceanProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DigitalOceanProviderConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DigitalOceanProviderConfigList contains a list of DigitalOceanProviderConfig
+type DigitalOceanProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DigitalOceanProviderConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DigitalOceanProviderConfig{}, &DigitalOceanProviderConfigList{})
+}
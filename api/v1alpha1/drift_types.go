@@ -0,0 +1,42 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// DriftPolicyObserve reports drift without correcting it.
+	DriftPolicyObserve = "Observe"
+	// DriftPolicyEnforce reports drift and changes the resource back to
+	// match the spec.
+	DriftPolicyEnforce = "Enforce"
+
+	// ConditionTypeDrifted is true when a resource's settings in
+	// DigitalOcean don't match its spec.
+	ConditionTypeDrifted = "Drifted"
+)
+
+// FieldDrift is a difference between a spec field and the resource in
+// DigitalOcean.
+type FieldDrift struct {
+	// Field is the path of the spec field, e.g. spec.size.
+	Field string `json:"field"`
+	// Desired is the value in the spec.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+	// Actual is the value in DigitalOcean.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+}
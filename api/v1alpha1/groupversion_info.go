@@ -33,8 +33,24 @@ const (
 	DatabaseUserKind = "DatabaseUser"
 	// DatabaseUserReferenceKind is the kind of a DatabaseUserReference.
 	DatabaseUserReferenceKind = "DatabaseUserReference"
+	// KafkaSchemaSubjectKind is the kind of a KafkaSchemaSubject.
+	KafkaSchemaSubjectKind = "KafkaSchemaSubject"
+	// DatabaseLogsinkKind is the kind of a DatabaseLogsink.
+	DatabaseLogsinkKind = "DatabaseLogsink"
+	// DatabaseOnlineMigrationKind is the kind of a DatabaseOnlineMigration.
+	DatabaseOnlineMigrationKind = "DatabaseOnlineMigration"
+	// OpenSearchIndexPolicyKind is the kind of an OpenSearchIndexPolicy.
+	OpenSearchIndexPolicyKind = "OpenSearchIndexPolicy"
+	// DigitalOceanProviderConfigKind is the kind of a DigitalOceanProviderConfig.
+	DigitalOceanProviderConfigKind = "DigitalOceanProviderConfig"
+	// DatabaseClusterGrantKind is the kind of a DatabaseClusterGrant.
+	DatabaseClusterGrantKind = "DatabaseClusterGrant"
 )
 
+// ConditionTypeReady is true when a resource is reconciled and ready to use.
+// Every kind served as v1beta1 sets it.
+const ConditionTypeReady = "Ready"
+
 var (
 	// GroupVersion is group version used to register these objects
 	GroupVersion = schema.GroupVersion{Group: "databases.digitalocean.com", Version: "v1alpha1"}
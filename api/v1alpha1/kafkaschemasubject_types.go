@@ -0,0 +1,96 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KafkaSchemaSubjectSpec defines the desired state of KafkaSchemaSubject
+type KafkaSchemaSubjectSpec struct {
+	// Cluster is a reference to the DatabaseCluster or DatabaseClusterReference
+	// that represents the Kafka cluster whose schema registry holds the subject.
+	Cluster corev1.TypedLocalObjectReference `json:"databaseCluster"`
+	// SubjectName is the name of the schema registry subject.
+	SubjectName string `json:"subjectName"`
+	// SchemaType is the type of the schema.
+	// +kubebuilder:validation:Enum=AVRO;JSON;PROTOBUF
+	SchemaType string `json:"schemaType"`
+	// Schema is the inline schema definition. Exactly one of Schema and
+	// SchemaConfigMapRef must be set.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+	// SchemaConfigMapRef selects a key of a ConfigMap in the same namespace
+	// that holds the schema definition. Exactly one of Schema and
+	// SchemaConfigMapRef must be set.
+	// +optional
+	SchemaConfigMapRef *corev1.ConfigMapKeySelector `json:"schemaConfigMapRef,omitempty"`
+	// CompatibilityLevel is the compatibility level for the subject. If unset,
+	// the schema registry's global compatibility level applies.
+	// +kubebuilder:validation:Enum=NONE;BACKWARD;BACKWARD_TRANSITIVE;FORWARD;FORWARD_TRANSITIVE;FULL;FULL_TRANSITIVE
+	// +optional
+	CompatibilityLevel string `json:"compatibilityLevel,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// KafkaSchemaSubjectStatus defines the observed state of KafkaSchemaSubject
+type KafkaSchemaSubjectStatus struct {
+	// ClusterUUID is the UUID of the cluster this subject is in. We keep this
+	// in the status so that we can manage the subject even if the referenced
+	// Cluster CR is deleted.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// SchemaID is the schema registry ID of the latest registered schema.
+	SchemaID int64 `json:"schemaID,omitempty"`
+	// CompatibilityLevel is the compatibility level configured for the
+	// subject.
+	CompatibilityLevel string `json:"compatibilityLevel,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Subject",type=string,JSONPath=`.spec.subjectName`
+//+kubebuilder:printcolumn:name="Schema type",type=string,JSONPath=`.spec.schemaType`
+//+kubebuilder:printcolumn:name="Schema ID",type=integer,JSONPath=`.status.schemaID`
+
+// KafkaSchemaSubject is the Schema for the kafkaschemasubjects API
+type KafkaSchemaSubject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafkaSchemaSubjectSpec   `json:"spec,omitempty"`
+	Status KafkaSchemaSubjectStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KafkaSchemaSubjectList contains a list of KafkaSchemaSubject
+type KafkaSchemaSubjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafkaSchemaSubject `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafkaSchemaSubject{}, &KafkaSchemaSubjectList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenSearchIndexPolicySpec defines the desired state of OpenSearchIndexPolicy
+type OpenSearchIndexPolicySpec struct {
+	// Cluster is a reference to the DatabaseCluster or DatabaseClusterReference
+	// that represents the OpenSearch cluster whose indexes are managed.
+	Cluster corev1.TypedLocalObjectReference `json:"databaseCluster"`
+	// Rules are the retention rules for the cluster's indexes. An index is
+	// deleted if it falls outside of any rule whose pattern it matches.
+	// +kubebuilder:validation:MinItems=1
+	Rules []IndexRetentionRule `json:"rules"`
+	// DryRun disables deleting indexes. Indexes outside of the policy are only
+	// reported in the status.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// Interval is how often the policy is evaluated. Defaults to one hour.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// IndexRetentionRule describes how long indexes matching a pattern are kept.
+// At least one of MaxAge and MaxTotalSize must be set.
+type IndexRetentionRule struct {
+	// Pattern is a glob pattern, e.g., logs-*, matched against index names.
+	// Indexes whose names start with a dot are only matched by patterns that
+	// also start with a dot.
+	Pattern string `json:"pattern"`
+	// MaxAge is the maximum age of a matching index.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// MaxTotalSize is the maximum total size of all matching indexes. When it
+	// is exceeded, the oldest indexes are deleted first.
+	// +optional
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
+}
+
+// OpenSearchIndexPolicyStatus defines the observed state of OpenSearchIndexPolicy
+type OpenSearchIndexPolicyStatus struct {
+	// ClusterUUID is the UUID of the cluster the policy applies to.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// LastEvaluatedAt is the time the policy was last evaluated.
+	LastEvaluatedAt *metav1.Time `json:"lastEvaluatedAt,omitempty"`
+	// IndexesOutsidePolicy are the indexes that fell outside of the policy at
+	// the last evaluation. In dry-run mode, these are the indexes that would
+	// have been deleted.
+	IndexesOutsidePolicy []string `json:"indexesOutsidePolicy,omitempty"`
+	// DeletedIndexes are the indexes deleted at the last evaluation.
+	DeletedIndexes []string `json:"deletedIndexes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Dry run",type=boolean,JSONPath=`.spec.dryRun`
+//+kubebuilder:printcolumn:name="Last evaluated",type="date",JSONPath=`.status.lastEvaluatedAt`
+
+// OpenSearchIndexPolicy is the Schema for the opensearchindexpolicies API
+type OpenSearchIndexPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenSearchIndexPolicySpec   `json:"spec,omitempty"`
+	Status OpenSearchIndexPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OpenSearchIndexPolicyList contains a list of OpenSearchIndexPolicy
+type OpenSearchIndexPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenSearchIndexPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenSearchIndexPolicy{}, &OpenSearchIndexPolicyList{})
+}
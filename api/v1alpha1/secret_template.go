@@ -0,0 +1,120 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// SecretTemplateData holds the connection fields available to the templates
+// in a secretTemplate.
+// +kubebuilder:object:generate=false
+type SecretTemplateData struct {
+	// User is the username.
+	User string
+	// Password is the user's password.
+	Password string
+	// Host is the host of the database cluster.
+	Host string
+	// Port is the port of the database cluster.
+	Port int
+	// Database is the name of the logical database.
+	Database string
+	// SSLMode is "require" if the cluster requires SSL and "disable"
+	// otherwise.
+	SSLMode string
+	// CA is the PEM-encoded CA certificate of the database cluster.
+	CA string
+}
+
+// ExecuteSecretTemplate parses and executes the template for the given key of
+// a secretTemplate. Templates are Go text/templates over SecretTemplateData.
+func ExecuteSecretTemplate(key, text string, data *SecretTemplateData) (string, error) {
+	tmpl, err := template.New(key).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// SecretTemplateUsesCA returns whether the template for a key of a
+// secretTemplate may use the CA field, which has to be fetched separately from
+// the other connection fields. Templates that don't parse are assumed to use
+// it.
+func SecretTemplateUsesCA(key, text string) bool {
+	tmpl, err := template.New(key).Parse(text)
+	if err != nil {
+		return true
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && nodeUsesField(t.Tree.Root, "CA") {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeUsesField returns whether a template parse tree refers to a field with
+// the given name, on any value.
+func nodeUsesField(node parse.Node, name string) bool {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return false
+		}
+		return slices.ContainsFunc(node.Nodes, func(n parse.Node) bool {
+			return nodeUsesField(n, name)
+		})
+	case *parse.ActionNode:
+		return nodeUsesField(node.Pipe, name)
+	case *parse.PipeNode:
+		if node == nil {
+			return false
+		}
+		return slices.ContainsFunc(node.Cmds, func(cmd *parse.CommandNode) bool {
+			return nodeUsesField(cmd, name)
+		})
+	case *parse.CommandNode:
+		return slices.ContainsFunc(node.Args, func(n parse.Node) bool {
+			return nodeUsesField(n, name)
+		})
+	case *parse.IfNode:
+		return nodeUsesField(&node.BranchNode, name)
+	case *parse.RangeNode:
+		return nodeUsesField(&node.BranchNode, name)
+	case *parse.WithNode:
+		return nodeUsesField(&node.BranchNode, name)
+	case *parse.BranchNode:
+		return nodeUsesField(node.Pipe, name) || nodeUsesField(node.List, name) || nodeUsesField(node.ElseList, name)
+	case *parse.TemplateNode:
+		return nodeUsesField(node.Pipe, name)
+	case *parse.FieldNode:
+		return slices.Contains(node.Ident, name)
+	case *parse.VariableNode:
+		return slices.Contains(node.Ident, name)
+	case *parse.ChainNode:
+		return nodeUsesField(node.Node, name) || slices.Contains(node.Field, name)
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secret templates", func() {
+	It("should detect templates that use the CA", func() {
+		for _, text := range []string{
+			`{{ .CA }}`,
+			`{{ if .CA }}{{ .CA | printf "%q" }}{{ end }}`,
+			`{{ with $d := . }}{{ $d.CA }}{{ end }}`,
+			`{{ define "ca" }}{{ .CA }}{{ end }}{{ template "ca" . }}`,
+			`{{ range $i, $c := .CA }}{{ $c }}{{ end }}`,
+			`{{ .Host`,
+		} {
+			Expect(SecretTemplateUsesCA("key", text)).To(BeTrue(), text)
+		}
+	})
+
+	It("should detect templates that don't use the CA", func() {
+		for _, text := range []string{
+			``,
+			`postgresql://{{ .User }}:{{ .Password }}@{{ .Host }}:{{ .Port }}/{{ .Database }}?sslmode={{ .SSLMode }}`,
+			`{{ if eq .SSLMode "require" }}CA{{ else }}{{ .Host }}{{ end }}`,
+		} {
+			Expect(SecretTemplateUsesCA("key", text)).To(BeFalse(), text)
+		}
+	})
+})
@@ -21,15 +21,54 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterOutput) DeepCopyInto(out *ClusterOutput) {
+	*out = *in
+	in.OutputMetadata.DeepCopyInto(&out.OutputMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOutput.
+func (in *ClusterOutput) DeepCopy() *ClusterOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReference) DeepCopyInto(out *ClusterReference) {
+	*out = *in
+	if in.APIGroup != nil {
+		in, out := &in.APIGroup, &out.APIGroup
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReference.
+func (in *ClusterReference) DeepCopy() *ClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseCluster) DeepCopyInto(out *DatabaseCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -51,6 +90,89 @@ func (in *DatabaseCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterGrant) DeepCopyInto(out *DatabaseClusterGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterGrant.
+func (in *DatabaseClusterGrant) DeepCopy() *DatabaseClusterGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseClusterGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterGrantList) DeepCopyInto(out *DatabaseClusterGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseClusterGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterGrantList.
+func (in *DatabaseClusterGrantList) DeepCopy() *DatabaseClusterGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseClusterGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterGrantSpec) DeepCopyInto(out *DatabaseClusterGrantSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]GrantedCluster, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterGrantSpec.
+func (in *DatabaseClusterGrantSpec) DeepCopy() *DatabaseClusterGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseClusterList) DeepCopyInto(out *DatabaseClusterList) {
 	*out = *in
@@ -88,7 +210,7 @@ func (in *DatabaseClusterReference) DeepCopyInto(out *DatabaseClusterReference)
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -145,6 +267,16 @@ func (in *DatabaseClusterReferenceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseClusterReferenceSpec) DeepCopyInto(out *DatabaseClusterReferenceSpec) {
 	*out = *in
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(ClusterOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReferenceSpec.
@@ -161,6 +293,24 @@ func (in *DatabaseClusterReferenceSpec) DeepCopy() *DatabaseClusterReferenceSpec
 func (in *DatabaseClusterReferenceStatus) DeepCopyInto(out *DatabaseClusterReferenceStatus) {
 	*out = *in
 	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.EventsCursor != nil {
+		in, out := &in.EventsCursor, &out.EventsCursor
+		*out = new(DatabaseEventsCursor)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReferenceStatus.
@@ -176,6 +326,55 @@ func (in *DatabaseClusterReferenceStatus) DeepCopy() *DatabaseClusterReferenceSt
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
 	*out = *in
+	if in.StorageSizeMiB != nil {
+		in, out := &in.StorageSizeMiB, &out.StorageSizeMiB
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(DatabaseFirewall)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]v1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MetricsCredentialsRotationInterval != nil {
+		in, out := &in.MetricsCredentialsRotationInterval, &out.MetricsCredentialsRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(ClusterOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterSpec.
@@ -192,6 +391,33 @@ func (in *DatabaseClusterSpec) DeepCopy() *DatabaseClusterSpec {
 func (in *DatabaseClusterStatus) DeepCopyInto(out *DatabaseClusterStatus) {
 	*out = *in
 	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.EventsCursor != nil {
+		in, out := &in.EventsCursor, &out.EventsCursor
+		*out = new(DatabaseEventsCursor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsCredentialsRotatedAt != nil {
+		in, out := &in.MetricsCredentialsRotatedAt, &out.MetricsCredentialsRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterStatus.
@@ -205,7 +431,48 @@ func (in *DatabaseClusterStatus) DeepCopy() *DatabaseClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
+func (in *DatabaseEventsCursor) DeepCopyInto(out *DatabaseEventsCursor) {
+	*out = *in
+	in.LastEventTime.DeepCopyInto(&out.LastEventTime)
+	if in.LastEventIDs != nil {
+		in, out := &in.LastEventIDs, &out.LastEventIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseEventsCursor.
+func (in *DatabaseEventsCursor) DeepCopy() *DatabaseEventsCursor {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseEventsCursor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseFirewall) DeepCopyInto(out *DatabaseFirewall) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]FirewallRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseFirewall.
+func (in *DatabaseFirewall) DeepCopy() *DatabaseFirewall {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseFirewall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseLogsink) DeepCopyInto(out *DatabaseLogsink) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -213,18 +480,18 @@ func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
-func (in *DatabaseUser) DeepCopy() *DatabaseUser {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseLogsink.
+func (in *DatabaseLogsink) DeepCopy() *DatabaseLogsink {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUser)
+	out := new(DatabaseLogsink)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatabaseUser) DeepCopyObject() runtime.Object {
+func (in *DatabaseLogsink) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -232,31 +499,31 @@ func (in *DatabaseUser) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserList) DeepCopyInto(out *DatabaseUserList) {
+func (in *DatabaseLogsinkList) DeepCopyInto(out *DatabaseLogsinkList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DatabaseUser, len(*in))
+		*out = make([]DatabaseLogsink, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserList.
-func (in *DatabaseUserList) DeepCopy() *DatabaseUserList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseLogsinkList.
+func (in *DatabaseLogsinkList) DeepCopy() *DatabaseLogsinkList {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserList)
+	out := new(DatabaseLogsinkList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatabaseUserList) DeepCopyObject() runtime.Object {
+func (in *DatabaseLogsinkList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -264,7 +531,68 @@ func (in *DatabaseUserList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserReference) DeepCopyInto(out *DatabaseUserReference) {
+func (in *DatabaseLogsinkSpec) DeepCopyInto(out *DatabaseLogsinkSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Rsyslog != nil {
+		in, out := &in.Rsyslog, &out.Rsyslog
+		*out = new(RsyslogLogsinkConfig)
+		**out = **in
+	}
+	if in.Elasticsearch != nil {
+		in, out := &in.Elasticsearch, &out.Elasticsearch
+		*out = new(ElasticsearchLogsinkConfig)
+		**out = **in
+	}
+	if in.Opensearch != nil {
+		in, out := &in.Opensearch, &out.Opensearch
+		*out = new(ElasticsearchLogsinkConfig)
+		**out = **in
+	}
+	if in.Datadog != nil {
+		in, out := &in.Datadog, &out.Datadog
+		*out = new(DatadogLogsinkConfig)
+		**out = **in
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseLogsinkSpec.
+func (in *DatabaseLogsinkSpec) DeepCopy() *DatabaseLogsinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseLogsinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseLogsinkStatus) DeepCopyInto(out *DatabaseLogsinkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseLogsinkStatus.
+func (in *DatabaseLogsinkStatus) DeepCopy() *DatabaseLogsinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseLogsinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseOnlineMigration) DeepCopyInto(out *DatabaseOnlineMigration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -272,18 +600,18 @@ func (in *DatabaseUserReference) DeepCopyInto(out *DatabaseUserReference) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReference.
-func (in *DatabaseUserReference) DeepCopy() *DatabaseUserReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOnlineMigration.
+func (in *DatabaseOnlineMigration) DeepCopy() *DatabaseOnlineMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserReference)
+	out := new(DatabaseOnlineMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatabaseUserReference) DeepCopyObject() runtime.Object {
+func (in *DatabaseOnlineMigration) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -291,31 +619,31 @@ func (in *DatabaseUserReference) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserReferenceList) DeepCopyInto(out *DatabaseUserReferenceList) {
+func (in *DatabaseOnlineMigrationList) DeepCopyInto(out *DatabaseOnlineMigrationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DatabaseUserReference, len(*in))
+		*out = make([]DatabaseOnlineMigration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceList.
-func (in *DatabaseUserReferenceList) DeepCopy() *DatabaseUserReferenceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOnlineMigrationList.
+func (in *DatabaseOnlineMigrationList) DeepCopy() *DatabaseOnlineMigrationList {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserReferenceList)
+	out := new(DatabaseOnlineMigrationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DatabaseUserReferenceList) DeepCopyObject() runtime.Object {
+func (in *DatabaseOnlineMigrationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -323,63 +651,930 @@ func (in *DatabaseUserReferenceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserReferenceSpec) DeepCopyInto(out *DatabaseUserReferenceSpec) {
+func (in *DatabaseOnlineMigrationSpec) DeepCopyInto(out *DatabaseOnlineMigrationSpec) {
 	*out = *in
 	in.Cluster.DeepCopyInto(&out.Cluster)
+	out.Source = in.Source
+	if in.IgnoreDBs != nil {
+		in, out := &in.IgnoreDBs, &out.IgnoreDBs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceSpec.
-func (in *DatabaseUserReferenceSpec) DeepCopy() *DatabaseUserReferenceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOnlineMigrationSpec.
+func (in *DatabaseOnlineMigrationSpec) DeepCopy() *DatabaseOnlineMigrationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserReferenceSpec)
+	out := new(DatabaseOnlineMigrationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserReferenceStatus) DeepCopyInto(out *DatabaseUserReferenceStatus) {
+func (in *DatabaseOnlineMigrationStatus) DeepCopyInto(out *DatabaseOnlineMigrationStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceStatus.
-func (in *DatabaseUserReferenceStatus) DeepCopy() *DatabaseUserReferenceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseOnlineMigrationStatus.
+func (in *DatabaseOnlineMigrationStatus) DeepCopy() *DatabaseOnlineMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserReferenceStatus)
+	out := new(DatabaseOnlineMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserSpec) DeepCopyInto(out *DatabaseUserSpec) {
+func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
 	*out = *in
-	in.Cluster.DeepCopyInto(&out.Cluster)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserSpec.
-func (in *DatabaseUserSpec) DeepCopy() *DatabaseUserSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
+func (in *DatabaseUser) DeepCopy() *DatabaseUser {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserSpec)
+	out := new(DatabaseUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DatabaseUserStatus) DeepCopyInto(out *DatabaseUserStatus) {
+func (in *DatabaseUserList) DeepCopyInto(out *DatabaseUserList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserStatus.
-func (in *DatabaseUserStatus) DeepCopy() *DatabaseUserStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserList.
+func (in *DatabaseUserList) DeepCopy() *DatabaseUserList {
 	if in == nil {
 		return nil
 	}
-	out := new(DatabaseUserStatus)
+	out := new(DatabaseUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReference) DeepCopyInto(out *DatabaseUserReference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReference.
+func (in *DatabaseUserReference) DeepCopy() *DatabaseUserReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserReference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceList) DeepCopyInto(out *DatabaseUserReferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseUserReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceList.
+func (in *DatabaseUserReferenceList) DeepCopy() *DatabaseUserReferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserReferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceSpec) DeepCopyInto(out *DatabaseUserReferenceSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(UserOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceSpec.
+func (in *DatabaseUserReferenceSpec) DeepCopy() *DatabaseUserReferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceStatus) DeepCopyInto(out *DatabaseUserReferenceStatus) {
+	*out = *in
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceStatus.
+func (in *DatabaseUserReferenceStatus) DeepCopy() *DatabaseUserReferenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserSpec) DeepCopyInto(out *DatabaseUserSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.ACL != nil {
+		in, out := &in.ACL, &out.ACL
+		*out = make([]KafkaACL, len(*in))
+		copy(*out, *in)
+	}
+	if in.OpenSearch != nil {
+		in, out := &in.OpenSearch, &out.OpenSearch
+		*out = new(OpenSearchUserSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MongoDB != nil {
+		in, out := &in.MongoDB, &out.MongoDB
+		*out = new(MongoDBUserSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(UserOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserSpec.
+func (in *DatabaseUserSpec) DeepCopy() *DatabaseUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserStatus) DeepCopyInto(out *DatabaseUserStatus) {
+	*out = *in
+	if in.LastRotatedAt != nil {
+		in, out := &in.LastRotatedAt, &out.LastRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LostPasswordResetAt != nil {
+		in, out := &in.LostPasswordResetAt, &out.LostPasswordResetAt
+		*out = (*in).DeepCopy()
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserStatus.
+func (in *DatabaseUserStatus) DeepCopy() *DatabaseUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogLogsinkConfig) DeepCopyInto(out *DatadogLogsinkConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogLogsinkConfig.
+func (in *DatadogLogsinkConfig) DeepCopy() *DatadogLogsinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogLogsinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigitalOceanProviderConfig) DeepCopyInto(out *DigitalOceanProviderConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigitalOceanProviderConfig.
+func (in *DigitalOceanProviderConfig) DeepCopy() *DigitalOceanProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DigitalOceanProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DigitalOceanProviderConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigitalOceanProviderConfigList) DeepCopyInto(out *DigitalOceanProviderConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DigitalOceanProviderConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigitalOceanProviderConfigList.
+func (in *DigitalOceanProviderConfigList) DeepCopy() *DigitalOceanProviderConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(DigitalOceanProviderConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DigitalOceanProviderConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DigitalOceanProviderConfigSpec) DeepCopyInto(out *DigitalOceanProviderConfigSpec) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DigitalOceanProviderConfigSpec.
+func (in *DigitalOceanProviderConfigSpec) DeepCopy() *DigitalOceanProviderConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DigitalOceanProviderConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchLogsinkConfig) DeepCopyInto(out *ElasticsearchLogsinkConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchLogsinkConfig.
+func (in *ElasticsearchLogsinkConfig) DeepCopy() *ElasticsearchLogsinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchLogsinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldDrift) DeepCopyInto(out *FieldDrift) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldDrift.
+func (in *FieldDrift) DeepCopy() *FieldDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallRule) DeepCopyInto(out *FirewallRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallRule.
+func (in *FirewallRule) DeepCopy() *FirewallRule {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrantedCluster) DeepCopyInto(out *GrantedCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrantedCluster.
+func (in *GrantedCluster) DeepCopy() *GrantedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(GrantedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexRetentionRule) DeepCopyInto(out *IndexRetentionRule) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxTotalSize != nil {
+		in, out := &in.MaxTotalSize, &out.MaxTotalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexRetentionRule.
+func (in *IndexRetentionRule) DeepCopy() *IndexRetentionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexRetentionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaACL) DeepCopyInto(out *KafkaACL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaACL.
+func (in *KafkaACL) DeepCopy() *KafkaACL {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSchemaSubject) DeepCopyInto(out *KafkaSchemaSubject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaSubject.
+func (in *KafkaSchemaSubject) DeepCopy() *KafkaSchemaSubject {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSchemaSubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaSchemaSubject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSchemaSubjectList) DeepCopyInto(out *KafkaSchemaSubjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KafkaSchemaSubject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaSubjectList.
+func (in *KafkaSchemaSubjectList) DeepCopy() *KafkaSchemaSubjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSchemaSubjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaSchemaSubjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSchemaSubjectSpec) DeepCopyInto(out *KafkaSchemaSubjectSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.SchemaConfigMapRef != nil {
+		in, out := &in.SchemaConfigMapRef, &out.SchemaConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaSubjectSpec.
+func (in *KafkaSchemaSubjectSpec) DeepCopy() *KafkaSchemaSubjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSchemaSubjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaSchemaSubjectStatus) DeepCopyInto(out *KafkaSchemaSubjectStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaSubjectStatus.
+func (in *KafkaSchemaSubjectStatus) DeepCopy() *KafkaSchemaSubjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaSchemaSubjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBUserSettings) DeepCopyInto(out *MongoDBUserSettings) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUserSettings.
+func (in *MongoDBUserSettings) DeepCopy() *MongoDBUserSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBUserSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnlineMigrationSource) DeepCopyInto(out *OnlineMigrationSource) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnlineMigrationSource.
+func (in *OnlineMigrationSource) DeepCopy() *OnlineMigrationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OnlineMigrationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchACL) DeepCopyInto(out *OpenSearchACL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchACL.
+func (in *OpenSearchACL) DeepCopy() *OpenSearchACL {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchIndexPolicy) DeepCopyInto(out *OpenSearchIndexPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchIndexPolicy.
+func (in *OpenSearchIndexPolicy) DeepCopy() *OpenSearchIndexPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchIndexPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenSearchIndexPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchIndexPolicyList) DeepCopyInto(out *OpenSearchIndexPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenSearchIndexPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchIndexPolicyList.
+func (in *OpenSearchIndexPolicyList) DeepCopy() *OpenSearchIndexPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchIndexPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenSearchIndexPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchIndexPolicySpec) DeepCopyInto(out *OpenSearchIndexPolicySpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]IndexRetentionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchIndexPolicySpec.
+func (in *OpenSearchIndexPolicySpec) DeepCopy() *OpenSearchIndexPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchIndexPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchIndexPolicyStatus) DeepCopyInto(out *OpenSearchIndexPolicyStatus) {
+	*out = *in
+	if in.LastEvaluatedAt != nil {
+		in, out := &in.LastEvaluatedAt, &out.LastEvaluatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexesOutsidePolicy != nil {
+		in, out := &in.IndexesOutsidePolicy, &out.IndexesOutsidePolicy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedIndexes != nil {
+		in, out := &in.DeletedIndexes, &out.DeletedIndexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchIndexPolicyStatus.
+func (in *OpenSearchIndexPolicyStatus) DeepCopy() *OpenSearchIndexPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchIndexPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchUserSettings) DeepCopyInto(out *OpenSearchUserSettings) {
+	*out = *in
+	if in.ACL != nil {
+		in, out := &in.ACL, &out.ACL
+		*out = make([]OpenSearchACL, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchUserSettings.
+func (in *OpenSearchUserSettings) DeepCopy() *OpenSearchUserSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchUserSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputMetadata) DeepCopyInto(out *OutputMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputMetadata.
+func (in *OutputMetadata) DeepCopy() *OutputMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
+func (in *OutputStatus) DeepCopy() *OutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotation) DeepCopyInto(out *PasswordRotation) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RolloutTargets != nil {
+		in, out := &in.RolloutTargets, &out.RolloutTargets
+		*out = make([]RolloutTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotation.
+func (in *PasswordRotation) DeepCopy() *PasswordRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderCredentialsSecretReference) DeepCopyInto(out *ProviderCredentialsSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCredentialsSecretReference.
+func (in *ProviderCredentialsSecretReference) DeepCopy() *ProviderCredentialsSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderCredentialsSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutTarget) DeepCopyInto(out *RolloutTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTarget.
+func (in *RolloutTarget) DeepCopy() *RolloutTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RsyslogLogsinkConfig) DeepCopyInto(out *RsyslogLogsinkConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RsyslogLogsinkConfig.
+func (in *RsyslogLogsinkConfig) DeepCopy() *RsyslogLogsinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RsyslogLogsinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOutput) DeepCopyInto(out *UserOutput) {
+	*out = *in
+	in.OutputMetadata.DeepCopyInto(&out.OutputMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOutput.
+func (in *UserOutput) DeepCopy() *UserOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOutput)
 	in.DeepCopyInto(out)
 	return out
 }
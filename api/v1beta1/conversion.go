@@ -0,0 +1,32 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// v1beta1 is the hub version that the other versions of the databases API
+// convert to and from.
+
+// Hub marks this type as a conversion hub.
+func (*DatabaseCluster) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*DatabaseClusterReference) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*DatabaseUser) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*DatabaseUserReference) Hub() {}
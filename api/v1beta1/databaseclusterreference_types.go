@@ -0,0 +1,106 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseClusterReferenceSpec defines the desired state of DatabaseClusterReference
+type DatabaseClusterReferenceSpec struct {
+	// UUID is the UUID of an existing database.
+	UUID string `json:"uuid"`
+	// Output configures the generated ConfigMaps and Secret.
+	// +optional
+	Output *ClusterOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// DatabaseClusterReferenceStatus defines the observed state of DatabaseClusterReference
+type DatabaseClusterReferenceStatus struct {
+	// Engine is the database engine to use.
+	Engine string `json:"engine,omitempty"`
+	// Name is the name of the database cluster.
+	Name string `json:"name,omitempty"`
+	// Version is the DB version to use.
+	Version string `json:"version,omitempty"`
+	// NumNodes is the number of nodes in the database cluster.
+	NumNodes int64 `json:"numNodes,omitempty"`
+	// Size is the slug of the node size to use.
+	Size string `json:"size,omitempty"`
+	// Region is the slug of the DO region for the cluster.
+	Region string `json:"region,omitempty"`
+	// Status is the status of the database cluster.
+	Status string `json:"status,omitempty"`
+	// CreatedAt is the time at which the database cluster was created.
+	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// ConnectionHash is a hash of the database cluster's connection details,
+	// which changes when its hosts, ports or default database do.
+	ConnectionHash string `json:"connectionHash,omitempty"`
+	// EventsCursor records the database cluster's events that have been
+	// emitted as Kubernetes Events.
+	EventsCursor *DatabaseEventsCursor `json:"eventsCursor,omitempty"`
+	// Outputs records the names of the generated ConfigMaps and Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the default credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Conditions describe the state of the referenced cluster. The Ready
+	// condition is true while the cluster is online and the last reconcile
+	// succeeded.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Engine",type=string,JSONPath=`.status.engine`
+// +kubebuilder:printcolumn:name="Cluster name",type=string,JSONPath=`.status.name`
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+
+// DatabaseClusterReference is the Schema for the databaseclusterreferences API
+type DatabaseClusterReference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseClusterReferenceSpec   `json:"spec,omitempty"`
+	Status DatabaseClusterReferenceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseClusterReferenceList contains a list of DatabaseClusterReference
+type DatabaseClusterReferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseClusterReference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseClusterReference{}, &DatabaseClusterReferenceList{})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseUserSpec defines the desired state of DatabaseUser
+type DatabaseUserSpec struct {
+	// ClusterRef is a reference to the DatabaseCluster or
+	// DatabaseClusterReference that represents the database cluster in which
+	// the user will be created.
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// Username is the username for the user.
+	Username string `json:"username"`
+	// Database is the logical database to use in the connection details in
+	// the user's credentials Secret. Defaults to the cluster's default
+	// database.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// MySQLAuthPlugin is the authentication plugin used by a MySQL user. It
+	// defaults to caching_sha2_password; use mysql_native_password for clients
+	// that don't support it. Changing it resets the user's password. It is
+	// only supported for MySQL clusters.
+	// +kubebuilder:validation:Enum=caching_sha2_password;mysql_native_password
+	// +optional
+	MySQLAuthPlugin string `json:"mysqlAuthPlugin,omitempty"`
+	// ACL is the list of topics a Kafka user can access. It is only supported
+	// for Kafka clusters.
+	// +optional
+	ACL []KafkaACL `json:"acl,omitempty"`
+	// OpenSearch holds settings for OpenSearch users. It is only supported for
+	// OpenSearch clusters.
+	// +optional
+	OpenSearch *OpenSearchUserSettings `json:"opensearch,omitempty"`
+	// MongoDB holds settings for MongoDB users. It is only supported for
+	// MongoDB clusters.
+	// +optional
+	MongoDB *MongoDBUserSettings `json:"mongodb,omitempty"`
+	// PasswordRotation configures rotation of the user's password.
+	// +optional
+	PasswordRotation *PasswordRotation `json:"passwordRotation,omitempty"`
+	// ResetPasswordWhenLost resets the user's password if its credentials
+	// Secret is deleted, or its password removed, and the password can't be
+	// fetched from the API, as is the case for MongoDB users. Otherwise the
+	// Secret can't be restored for those users.
+	// +optional
+	ResetPasswordWhenLost bool `json:"resetPasswordWhenLost,omitempty"`
+	// Output configures the generated credentials Secret.
+	// +optional
+	Output *DatabaseUserOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// ClusterReference is a reference to a DatabaseCluster or
+// DatabaseClusterReference, which may be in another namespace.
+// +structType=atomic
+type ClusterReference struct {
+	// Kind is the type of resource being referenced, DatabaseCluster or
+	// DatabaseClusterReference.
+	Kind string `json:"kind"`
+	// Name is the name of the resource being referenced.
+	Name string `json:"name"`
+	// Namespace is the namespace of the resource being referenced. Defaults
+	// to the namespace of the referencing object. Referencing a resource in
+	// another namespace requires a DatabaseClusterGrant in that namespace
+	// that allows it.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PasswordRotation configures rotation of a user's password. Passwords can
+// also be rotated on demand with the rotate-password annotation.
+type PasswordRotation struct {
+	// Interval is how often the password is rotated. If unset, the password
+	// is only rotated on demand.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// RolloutTargets are workloads in the user's namespace that use the
+	// user's credentials. Their pod templates are annotated with a hash of the
+	// credentials so that they roll out when the password is rotated.
+	// +optional
+	RolloutTargets []RolloutTarget `json:"rolloutTargets,omitempty"`
+}
+
+// RolloutTarget is a workload that rolls out when credentials change.
+type RolloutTarget struct {
+	// Kind is the kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	Kind string `json:"kind"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+}
+
+// KafkaACL grants a Kafka user a permission on the topics matching a pattern.
+type KafkaACL struct {
+	// Topic is the name of a topic, or a pattern matching topic names using *
+	// wildcards.
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+	// Permission is the permission granted on the matching topics.
+	// +kubebuilder:validation:Enum=admin;consume;produce;produceconsume
+	Permission string `json:"permission"`
+}
+
+// OpenSearchUserSettings are the settings for an OpenSearch user.
+type OpenSearchUserSettings struct {
+	// ACL is the list of indexes the user can access.
+	ACL []OpenSearchACL `json:"acl,omitempty"`
+}
+
+// OpenSearchACL grants an OpenSearch user a permission on the indexes matching
+// a pattern.
+type OpenSearchACL struct {
+	// Index is the name of an index, or a pattern matching index names using *
+	// wildcards.
+	// +kubebuilder:validation:MinLength=1
+	Index string `json:"index"`
+	// Permission is the permission granted on the matching indexes.
+	// +kubebuilder:validation:Enum=deny;admin;read;readwrite;write
+	Permission string `json:"permission"`
+}
+
+// MongoDBUserSettings are the settings for a MongoDB user.
+type MongoDBUserSettings struct {
+	// Databases are the databases the role applies to.
+	Databases []string `json:"databases,omitempty"`
+	// Role is the user's role in the databases.
+	// +kubebuilder:validation:Enum=readOnly;readWrite;dbAdmin
+	Role string `json:"role"`
+}
+
+// DatabaseUserStatus defines the observed state of DatabaseUser
+type DatabaseUserStatus struct {
+	// ClusterUUID is the UUID of the cluster this user is in. We keep this in
+	// the status so that we can manage the user even if the referenced Cluster
+	// CR is deleted.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// Role is the user's role.
+	Role string `json:"role,omitempty"`
+	// LastRotatedAt is the time at which the user's password was last rotated.
+	LastRotatedAt *metav1.Time `json:"lastRotatedAt,omitempty"`
+	// LastRotationRequest is the value of the rotate-password annotation
+	// that was last handled.
+	LastRotationRequest string `json:"lastRotationRequest,omitempty"`
+	// LostPasswordResetAt is the time at which the user's password was last
+	// reset because its credentials Secret was lost.
+	LostPasswordResetAt *metav1.Time `json:"lostPasswordResetAt,omitempty"`
+	// CredentialsHash is a hash of the credentials in the user's Secret. It is
+	// set on the pod templates of the rollout targets.
+	CredentialsHash string `json:"credentialsHash,omitempty"`
+	// Outputs records the names of the generated credentials Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Conditions describe the state of the user. The Ready condition is
+	// true once the user exists and its credentials Secret is up to date.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Cluster name",type=string,JSONPath=`.spec.clusterRef.name`
+//+kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.spec.username`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.status.role`
+
+// DatabaseUser is the Schema for the databaseusers API
+type DatabaseUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseUserSpec   `json:"spec,omitempty"`
+	Status DatabaseUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseUserList contains a list of DatabaseUser
+type DatabaseUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseUser{}, &DatabaseUserList{})
+}
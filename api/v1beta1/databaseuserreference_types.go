@@ -0,0 +1,98 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseUserReferenceSpec defines the desired state of DatabaseUserReference
+type DatabaseUserReferenceSpec struct {
+	// ClusterRef is a reference to the DatabaseCluster or
+	// DatabaseClusterReference that represents the database cluster in which
+	// the user exists.
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// Username is the username of the referenced user.
+	Username string `json:"username"`
+	// Database is the logical database to use in the connection details in
+	// the user's credentials Secret. Defaults to the cluster's default
+	// database.
+	// +optional
+	Database string `json:"database,omitempty"`
+	// Output configures the generated credentials Secret.
+	// +optional
+	Output *UserOutput `json:"output,omitempty"`
+	// ProviderConfigRef names the DigitalOceanProviderConfig, in the same
+	// namespace, whose API token is used for this object. Defaults to the
+	// provider config named "default" in the namespace if there is one, and
+	// otherwise to the operator's own API token.
+	// +optional
+	ProviderConfigRef *corev1.LocalObjectReference `json:"providerConfigRef,omitempty"`
+}
+
+// DatabaseUserReferenceStatus defines the observed state of DatabaseUserReference
+type DatabaseUserReferenceStatus struct {
+	// ClusterUUID is the UUID of the cluster this user is in. We keep this in
+	// the status so that we can reference the user even if the referenced
+	// Cluster CR is deleted.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+	// Role is the user's role.
+	Role string `json:"role,omitempty"`
+	// Outputs records the names of the generated credentials Secret.
+	Outputs OutputStatus `json:"outputs,omitempty"`
+	// Binding is the credentials Secret, exposed for workloads using the
+	// Service Binding for Kubernetes specification.
+	Binding *corev1.LocalObjectReference `json:"binding,omitempty"`
+	// Conditions describe the state of the user reference. The Ready
+	// condition is true once its credentials Secret is up to date.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:metadata:annotations="servicebinding.io/provisioned-service=true"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:printcolumn:name="Cluster name",type=string,JSONPath=`.spec.clusterRef.name`
+//+kubebuilder:printcolumn:name="Username",type=string,JSONPath=`.spec.username`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.status.role`
+
+// DatabaseUserReference is the Schema for the databaseuserreferences API
+type DatabaseUserReference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseUserReferenceSpec   `json:"spec,omitempty"`
+	Status DatabaseUserReferenceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatabaseUserReferenceList contains a list of DatabaseUserReference
+type DatabaseUserReferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseUserReference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseUserReference{}, &DatabaseUserReferenceList{})
+}
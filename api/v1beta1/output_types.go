@@ -0,0 +1,96 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// OutputMetadata is extra metadata to set on generated objects.
+type OutputMetadata struct {
+	// Labels are added to the generated objects.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the generated objects.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ClusterOutput configures the ConfigMaps and Secret generated for a
+// database cluster.
+type ClusterOutput struct {
+	// ConnectionConfigMapName is the name of the ConfigMap holding the
+	// public connection details. Defaults to <name>-connection.
+	// +optional
+	ConnectionConfigMapName string `json:"connectionConfigMapName,omitempty"`
+	// PrivateConnectionConfigMapName is the name of the ConfigMap holding the
+	// private connection details. Defaults to <name>-private-connection.
+	// +optional
+	PrivateConnectionConfigMapName string `json:"privateConnectionConfigMapName,omitempty"`
+	// CredentialsSecretName is the name of the Secret holding the default
+	// user's credentials. Defaults to <name>-default-credentials.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	OutputMetadata `json:",inline"`
+}
+
+// DatabaseClusterOutput configures the ConfigMaps and Secret generated for a
+// DatabaseCluster, whose default user's credentials Secret can also be
+// templated.
+type DatabaseClusterOutput struct {
+	ClusterOutput `json:",inline"`
+
+	// SecretTemplate adds keys to the default user's credentials Secret.
+	// Each value is a Go template over the connection fields .User,
+	// .Password, .Host, .Port, .Database, .SSLMode and .CA. A templated key
+	// replaces the default key of the same name.
+	// +optional
+	SecretTemplate map[string]string `json:"secretTemplate,omitempty"`
+}
+
+// UserOutput configures the Secret generated for a database user.
+type UserOutput struct {
+	// CredentialsSecretName is the name of the Secret holding the user's
+	// credentials. Defaults to <name>-credentials.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	OutputMetadata `json:",inline"`
+}
+
+// DatabaseUserOutput configures the Secret generated for a DatabaseUser,
+// which can also be templated.
+type DatabaseUserOutput struct {
+	UserOutput `json:",inline"`
+
+	// SecretTemplate adds keys to the user's credentials Secret. Each value
+	// is a Go template over the connection fields .User, .Password, .Host,
+	// .Port, .Database, .SSLMode and .CA. A templated key replaces the
+	// default key of the same name.
+	// +optional
+	SecretTemplate map[string]string `json:"secretTemplate,omitempty"`
+}
+
+// OutputStatus records the names of the generated ConfigMaps and Secrets, so
+// that the old objects can be deleted when they're renamed.
+type OutputStatus struct {
+	// ConnectionConfigMapName is the name of the generated public connection
+	// ConfigMap.
+	ConnectionConfigMapName string `json:"connectionConfigMapName,omitempty"`
+	// PrivateConnectionConfigMapName is the name of the generated private
+	// connection ConfigMap.
+	PrivateConnectionConfigMapName string `json:"privateConnectionConfigMapName,omitempty"`
+	// CredentialsSecretName is the name of the generated credentials Secret.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
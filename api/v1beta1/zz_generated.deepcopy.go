@@ -0,0 +1,892 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterOutput) DeepCopyInto(out *ClusterOutput) {
+	*out = *in
+	in.OutputMetadata.DeepCopyInto(&out.OutputMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOutput.
+func (in *ClusterOutput) DeepCopy() *ClusterOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReference) DeepCopyInto(out *ClusterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReference.
+func (in *ClusterReference) DeepCopy() *ClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseCluster) DeepCopyInto(out *DatabaseCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseCluster.
+func (in *DatabaseCluster) DeepCopy() *DatabaseCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterList) DeepCopyInto(out *DatabaseClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterList.
+func (in *DatabaseClusterList) DeepCopy() *DatabaseClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterOutput) DeepCopyInto(out *DatabaseClusterOutput) {
+	*out = *in
+	in.ClusterOutput.DeepCopyInto(&out.ClusterOutput)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterOutput.
+func (in *DatabaseClusterOutput) DeepCopy() *DatabaseClusterOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterReference) DeepCopyInto(out *DatabaseClusterReference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReference.
+func (in *DatabaseClusterReference) DeepCopy() *DatabaseClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseClusterReference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterReferenceList) DeepCopyInto(out *DatabaseClusterReferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseClusterReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReferenceList.
+func (in *DatabaseClusterReferenceList) DeepCopy() *DatabaseClusterReferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterReferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseClusterReferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterReferenceSpec) DeepCopyInto(out *DatabaseClusterReferenceSpec) {
+	*out = *in
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(ClusterOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReferenceSpec.
+func (in *DatabaseClusterReferenceSpec) DeepCopy() *DatabaseClusterReferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterReferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterReferenceStatus) DeepCopyInto(out *DatabaseClusterReferenceStatus) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.EventsCursor != nil {
+		in, out := &in.EventsCursor, &out.EventsCursor
+		*out = new(DatabaseEventsCursor)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterReferenceStatus.
+func (in *DatabaseClusterReferenceStatus) DeepCopy() *DatabaseClusterReferenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterReferenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterSpec) DeepCopyInto(out *DatabaseClusterSpec) {
+	*out = *in
+	if in.StorageSizeMiB != nil {
+		in, out := &in.StorageSizeMiB, &out.StorageSizeMiB
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Firewall != nil {
+		in, out := &in.Firewall, &out.Firewall
+		*out = new(DatabaseFirewall)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]v1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MetricsCredentialsRotationInterval != nil {
+		in, out := &in.MetricsCredentialsRotationInterval, &out.MetricsCredentialsRotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(DatabaseClusterOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterSpec.
+func (in *DatabaseClusterSpec) DeepCopy() *DatabaseClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseClusterStatus) DeepCopyInto(out *DatabaseClusterStatus) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.EventsCursor != nil {
+		in, out := &in.EventsCursor, &out.EventsCursor
+		*out = new(DatabaseEventsCursor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsCredentialsRotatedAt != nil {
+		in, out := &in.MetricsCredentialsRotatedAt, &out.MetricsCredentialsRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseClusterStatus.
+func (in *DatabaseClusterStatus) DeepCopy() *DatabaseClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseEventsCursor) DeepCopyInto(out *DatabaseEventsCursor) {
+	*out = *in
+	in.LastEventTime.DeepCopyInto(&out.LastEventTime)
+	if in.LastEventIDs != nil {
+		in, out := &in.LastEventIDs, &out.LastEventIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseEventsCursor.
+func (in *DatabaseEventsCursor) DeepCopy() *DatabaseEventsCursor {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseEventsCursor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseFirewall) DeepCopyInto(out *DatabaseFirewall) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]FirewallRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseFirewall.
+func (in *DatabaseFirewall) DeepCopy() *DatabaseFirewall {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseFirewall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUser) DeepCopyInto(out *DatabaseUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUser.
+func (in *DatabaseUser) DeepCopy() *DatabaseUser {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserList) DeepCopyInto(out *DatabaseUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserList.
+func (in *DatabaseUserList) DeepCopy() *DatabaseUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserOutput) DeepCopyInto(out *DatabaseUserOutput) {
+	*out = *in
+	in.UserOutput.DeepCopyInto(&out.UserOutput)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserOutput.
+func (in *DatabaseUserOutput) DeepCopy() *DatabaseUserOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReference) DeepCopyInto(out *DatabaseUserReference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReference.
+func (in *DatabaseUserReference) DeepCopy() *DatabaseUserReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserReference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceList) DeepCopyInto(out *DatabaseUserReferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseUserReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceList.
+func (in *DatabaseUserReferenceList) DeepCopy() *DatabaseUserReferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseUserReferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceSpec) DeepCopyInto(out *DatabaseUserReferenceSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(UserOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceSpec.
+func (in *DatabaseUserReferenceSpec) DeepCopy() *DatabaseUserReferenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserReferenceStatus) DeepCopyInto(out *DatabaseUserReferenceStatus) {
+	*out = *in
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserReferenceStatus.
+func (in *DatabaseUserReferenceStatus) DeepCopy() *DatabaseUserReferenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserReferenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserSpec) DeepCopyInto(out *DatabaseUserSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.ACL != nil {
+		in, out := &in.ACL, &out.ACL
+		*out = make([]KafkaACL, len(*in))
+		copy(*out, *in)
+	}
+	if in.OpenSearch != nil {
+		in, out := &in.OpenSearch, &out.OpenSearch
+		*out = new(OpenSearchUserSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MongoDB != nil {
+		in, out := &in.MongoDB, &out.MongoDB
+		*out = new(MongoDBUserSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(DatabaseUserOutput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserSpec.
+func (in *DatabaseUserSpec) DeepCopy() *DatabaseUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseUserStatus) DeepCopyInto(out *DatabaseUserStatus) {
+	*out = *in
+	if in.LastRotatedAt != nil {
+		in, out := &in.LastRotatedAt, &out.LastRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LostPasswordResetAt != nil {
+		in, out := &in.LostPasswordResetAt, &out.LostPasswordResetAt
+		*out = (*in).DeepCopy()
+	}
+	out.Outputs = in.Outputs
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseUserStatus.
+func (in *DatabaseUserStatus) DeepCopy() *DatabaseUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldDrift) DeepCopyInto(out *FieldDrift) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldDrift.
+func (in *FieldDrift) DeepCopy() *FieldDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FirewallRule) DeepCopyInto(out *FirewallRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallRule.
+func (in *FirewallRule) DeepCopy() *FirewallRule {
+	if in == nil {
+		return nil
+	}
+	out := new(FirewallRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaACL) DeepCopyInto(out *KafkaACL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaACL.
+func (in *KafkaACL) DeepCopy() *KafkaACL {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MongoDBUserSettings) DeepCopyInto(out *MongoDBUserSettings) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MongoDBUserSettings.
+func (in *MongoDBUserSettings) DeepCopy() *MongoDBUserSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(MongoDBUserSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchACL) DeepCopyInto(out *OpenSearchACL) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchACL.
+func (in *OpenSearchACL) DeepCopy() *OpenSearchACL {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchUserSettings) DeepCopyInto(out *OpenSearchUserSettings) {
+	*out = *in
+	if in.ACL != nil {
+		in, out := &in.ACL, &out.ACL
+		*out = make([]OpenSearchACL, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchUserSettings.
+func (in *OpenSearchUserSettings) DeepCopy() *OpenSearchUserSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchUserSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputMetadata) DeepCopyInto(out *OutputMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputMetadata.
+func (in *OutputMetadata) DeepCopy() *OutputMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputStatus) DeepCopyInto(out *OutputStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputStatus.
+func (in *OutputStatus) DeepCopy() *OutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotation) DeepCopyInto(out *PasswordRotation) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RolloutTargets != nil {
+		in, out := &in.RolloutTargets, &out.RolloutTargets
+		*out = make([]RolloutTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotation.
+func (in *PasswordRotation) DeepCopy() *PasswordRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutTarget) DeepCopyInto(out *RolloutTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTarget.
+func (in *RolloutTarget) DeepCopy() *RolloutTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOutput) DeepCopyInto(out *UserOutput) {
+	*out = *in
+	in.OutputMetadata.DeepCopyInto(&out.OutputMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOutput.
+func (in *UserOutput) DeepCopy() *UserOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOutput)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,14 +17,21 @@ limitations under the License.
 package webhooks
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/do-operator/extgodo"
 	"github.com/digitalocean/godo"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -34,21 +41,133 @@ import (
 // log is for logging in this package.
 var databaseclusterlog = logf.Log.WithName("databasecluster-resource")
 
-func SetupDatabaseClusterWebhookWithManager(mgr ctrl.Manager, godoClient *godo.Client) error {
-	initGlobalGodoClient(godoClient)
+func SetupDatabaseClusterWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache, defaultRegion, driftPolicy string) error {
+	initGlobalGodoClients(godoClients)
 
 	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseCluster{}).
-		WithValidator(&DatabaseClusterValidator{}).
+		WithDefaulter(watchedOnlyDefaulter[*v1alpha1.DatabaseCluster](&DatabaseClusterDefaulter{DefaultRegion: defaultRegion})).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseCluster](&DatabaseClusterValidator{DriftPolicy: driftPolicy})).
 		Complete()
 }
 
+// +kubebuilder:webhook:path=/mutate-databases-digitalocean-com-v1alpha1-databasecluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=databaseclusters,verbs=create;update,versions=v1alpha1,name=mdatabasecluster.kb.io,admissionReviewVersions=v1
+
+// DatabaseClusterDefaulter fills in the optional fields of a new
+// DatabaseCluster's spec and records the values it chose in the
+// DefaultsAnnotation. On update, fields that were defaulted keep their
+// values when they're left out, e.g. by reapplying the original manifest.
+type DatabaseClusterDefaulter struct {
+	// DefaultRegion is the region for clusters that don't set one. If it's
+	// empty, clusters must set a region.
+	DefaultRegion string
+}
+
+// Default implements admission.Defaulter so a webhook will be registered for the type
+func (d *DatabaseClusterDefaulter) Default(ctx context.Context, cluster *v1alpha1.DatabaseCluster) error {
+	databaseclusterlog.Info("default", "name", cluster.Name)
+
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Update {
+		return keepDefaultedFields(req, cluster)
+	}
+
+	defaults := make(map[string]any)
+	if cluster.Spec.Name == "" && cluster.Name != "" {
+		cluster.Spec.Name = cluster.Name
+		defaults["spec.name"] = cluster.Spec.Name
+	}
+	if cluster.Spec.Region == "" && d.DefaultRegion != "" {
+		cluster.Spec.Region = d.DefaultRegion
+		defaults["spec.region"] = cluster.Spec.Region
+	}
+
+	if cluster.Spec.Version == "" || cluster.Spec.NumNodes == 0 {
+		godoClient, err := godoClientFor(ctx, cluster.Namespace, cluster.Spec.ProviderConfigRef)
+		if err != nil {
+			return err
+		}
+		opts, _, err := godoClient.Databases.ListOptions(ctx)
+		if err != nil {
+			return fmt.Errorf("getting database options from the DigitalOcean api: %v", err)
+		}
+		// Clusters with an unknown engine are left for the validating webhook
+		// to reject.
+		if engineOpts, ok := engineOptsFromOptions(opts, cluster.Spec.Engine); ok {
+			if cluster.Spec.Version == "" {
+				if version := newestVersion(engineOpts.Versions); version != "" {
+					cluster.Spec.Version = version
+					defaults["spec.version"] = cluster.Spec.Version
+				}
+			}
+			if cluster.Spec.NumNodes == 0 {
+				if numNodes := smallestNumNodes(engineOpts.Layouts); numNodes > 0 {
+					cluster.Spec.NumNodes = int64(numNodes)
+					defaults["spec.numNodes"] = cluster.Spec.NumNodes
+				}
+			}
+		}
+	}
+
+	if len(defaults) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return fmt.Errorf("encoding defaults: %v", err)
+	}
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[v1alpha1.DefaultsAnnotation] = string(data)
+	return nil
+}
+
+// keepDefaultedFields copies the defaultable fields that an update leaves
+// empty from the old cluster, since they're immutable once set.
+func keepDefaultedFields(req admission.Request, cluster *v1alpha1.DatabaseCluster) error {
+	var oldCluster v1alpha1.DatabaseCluster
+	if err := json.Unmarshal(req.OldObject.Raw, &oldCluster); err != nil {
+		return fmt.Errorf("decoding old DatabaseCluster: %v", err)
+	}
+	spec, oldSpec := &cluster.Spec, &oldCluster.Spec
+	spec.Name = cmp.Or(spec.Name, oldSpec.Name)
+	spec.Version = cmp.Or(spec.Version, oldSpec.Version)
+	spec.Region = cmp.Or(spec.Region, oldSpec.Region)
+	spec.NumNodes = cmp.Or(spec.NumNodes, oldSpec.NumNodes)
+	return nil
+}
+
 // +kubebuilder:webhook:path=/validate-databases-digitalocean-com-v1alpha1-databasecluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=databaseclusters,verbs=create;update,versions=v1alpha1,name=vdatabasecluster.kb.io,admissionReviewVersions=v1
-type DatabaseClusterValidator struct{}
+type DatabaseClusterValidator struct {
+	// DriftPolicy is the operator's drift policy for clusters that don't set
+	// one. Defaults to Observe.
+	DriftPolicy string
+}
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
 func (v *DatabaseClusterValidator) ValidateCreate(ctx context.Context, cluster *v1alpha1.DatabaseCluster) (warnings admission.Warnings, err error) {
 	databaseclusterlog.Info("validate create", "name", cluster.Name)
 
+	if err := validateMetricsCredentialsRotation(&cluster.Spec); err != nil {
+		return warnings, err
+	}
+	if err := validateSecretTemplate(cluster.Spec.SecretTemplate); err != nil {
+		return warnings, err
+	}
+	if err := validateClusterOutput(cluster.Spec.Output); err != nil {
+		return warnings, err
+	}
+	if err := validateClusterConfig(&cluster.Spec); err != nil {
+		return warnings, err
+	}
+	if err := v.validateFirewall(&cluster.Spec); err != nil {
+		return warnings, err
+	}
+
+	godoClient, err := godoClientFor(ctx, cluster.Namespace, cluster.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+
 	godoReq := cluster.Spec.ToGodoValidateCreateRequest()
 	req, err := godoClient.NewRequest(ctx, http.MethodPost, "/v2/databases", godoReq)
 	if err != nil {
@@ -143,7 +262,29 @@ func (v *DatabaseClusterValidator) ValidateUpdate(ctx context.Context, oldCluste
 	if oldCluster.Spec.Region != newCluster.Spec.Region {
 		return warnings, field.Forbidden(regionPath, "database region migrations are not yet supported in the do-operator")
 	}
+	if err := validateMetricsCredentialsRotation(&newCluster.Spec); err != nil {
+		return warnings, err
+	}
+	if err := validateSecretTemplate(newCluster.Spec.SecretTemplate); err != nil {
+		return warnings, err
+	}
+	if err := validateClusterOutput(newCluster.Spec.Output); err != nil {
+		return warnings, err
+	}
+	if err := validateClusterConfig(&newCluster.Spec); err != nil {
+		return warnings, err
+	}
+	if err := v.validateFirewall(&newCluster.Spec); err != nil {
+		return warnings, err
+	}
+	if err := validateProviderConfigRefUnchanged(oldCluster.Spec.ProviderConfigRef, newCluster.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
 
+	godoClient, err := godoClientFor(ctx, newCluster.Namespace, newCluster.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
 	opts, _, err := godoClient.Databases.ListOptions(ctx)
 	if err != nil {
 		return warnings, fmt.Errorf("getting database options from the DigitalOcean api: %v", err)
@@ -192,3 +333,91 @@ func (v *DatabaseClusterValidator) ValidateDelete(ctx context.Context, cluster *
 	databaseclusterlog.Info("validate delete", "name", cluster.Name)
 	return warnings, nil
 }
+
+// validateMetricsCredentialsRotation checks that the metrics credentials
+// aren't rotated so often that scrapers can't keep up.
+func validateMetricsCredentialsRotation(spec *v1alpha1.DatabaseClusterSpec) error {
+	interval := spec.MetricsCredentialsRotationInterval
+	if interval != nil && interval.Duration < time.Hour {
+		intervalPath := field.NewPath("spec").Child("metricsCredentialsRotationInterval")
+		return field.Invalid(intervalPath, interval.Duration.String(), "rotation interval must be at least one hour")
+	}
+	return nil
+}
+
+// validateFirewall rejects an empty list of firewall rules on clusters whose
+// drift is enforced, since enforcing it would remove every rule and open the
+// cluster to every source.
+func (v *DatabaseClusterValidator) validateFirewall(spec *v1alpha1.DatabaseClusterSpec) error {
+	if spec.Firewall == nil || len(spec.Firewall.Rules) > 0 {
+		return nil
+	}
+	driftPolicy := cmp.Or(spec.DriftPolicy, v.DriftPolicy, v1alpha1.DriftPolicyObserve)
+	if driftPolicy != v1alpha1.DriftPolicyEnforce {
+		return nil
+	}
+	rulesPath := field.NewPath("spec").Child("firewall").Child("rules")
+	return field.Required(rulesPath, "an empty list would remove every firewall rule under the Enforce drift policy; unset spec.firewall to leave the cluster's rules alone")
+}
+
+// validateClusterConfig checks that the configuration options set in the spec
+// exist for the cluster's engine.
+func validateClusterConfig(spec *v1alpha1.DatabaseClusterSpec) error {
+	if len(spec.Config) == 0 {
+		return nil
+	}
+	configPath := field.NewPath("spec").Child("config")
+	options, err := json.Marshal(spec.Config)
+	if err != nil {
+		return field.Invalid(configPath, spec.Config, err.Error())
+	}
+	if _, err := extgodo.DecodeDatabaseConfig(spec.Engine, options); err != nil {
+		return field.Invalid(configPath, string(options), err.Error())
+	}
+	return nil
+}
+
+// newestVersion returns the newest of the given engine versions, comparing
+// their dot-separated components numerically.
+func newestVersion(versions []string) string {
+	var newest string
+	for _, version := range versions {
+		if newest == "" || compareVersions(version, newest) > 0 {
+			newest = version
+		}
+	}
+	return newest
+}
+
+// compareVersions compares two dot-separated versions, such as "8" and
+// "7.2", returning -1, 0 or 1. Components that aren't numbers are compared
+// as strings.
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil {
+			if c := cmp.Compare(aNum, bNum); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(aParts), len(bParts))
+}
+
+// smallestNumNodes returns the smallest number of nodes among the given
+// layouts, or 0 if there are none.
+func smallestNumNodes(layouts []godo.DatabaseLayout) int {
+	var smallest int
+	for _, layout := range layouts {
+		if layout.NodeNum > 0 && (smallest == 0 || layout.NodeNum < smallest) {
+			smallest = layout.NodeNum
+		}
+	}
+	return smallest
+}
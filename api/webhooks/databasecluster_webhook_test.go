@@ -1,9 +1,13 @@
 package webhooks
 
 import (
+	"time"
+
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -59,6 +63,142 @@ var _ = Describe("DatabaseCluster validating webhook", func() {
 			err := k8sClient.Create(ctx, db)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should reject a metrics credentials rotation interval under an hour", func() {
+			db := &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fast-rotation-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:                             "mysql",
+					Name:                               "fast-rotation-db",
+					Version:                            "6",
+					NumNodes:                           1,
+					Size:                               "db-s-1vcpu-1gb",
+					Region:                             "dev0",
+					MetricsCredentialsRotationInterval: &metav1.Duration{Duration: time.Minute},
+				},
+			}
+
+			err := k8sClient.Create(ctx, db)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a secret template that doesn't parse", func() {
+			db := &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bad-template-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:   "mysql",
+					Name:     "bad-template-db",
+					Version:  "6",
+					NumNodes: 1,
+					Size:     "db-s-1vcpu-1gb",
+					Region:   "dev0",
+					SecretTemplate: map[string]string{
+						"DATABASE_URL": "{{ if .Host }}mysql://{{ .Host }}",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, db)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject the same name for both connection ConfigMaps", func() {
+			db := &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "duplicate-output-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:   "mysql",
+					Name:     "duplicate-output-db",
+					Version:  "6",
+					NumNodes: 1,
+					Size:     "db-s-1vcpu-1gb",
+					Region:   "dev0",
+					Output: &v1alpha1.ClusterOutput{
+						ConnectionConfigMapName:        "app-db",
+						PrivateConnectionConfigMapName: "app-db",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, db)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject config options the engine doesn't have", func() {
+			db := &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bad-config-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:   "mysql",
+					Name:     "bad-config-db",
+					Version:  "6",
+					NumNodes: 1,
+					Size:     "db-s-1vcpu-1gb",
+					Region:   "dev0",
+					Config: map[string]apiextensionsv1.JSON{
+						"autovacuum_max_workers": {Raw: []byte("5")},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, db)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject empty firewall rules only when drift is enforced", func() {
+			db := &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "open-firewall-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:      "mysql",
+					Name:        "open-firewall-db",
+					Version:     "6",
+					NumNodes:    1,
+					Size:        "db-s-1vcpu-1gb",
+					Region:      "dev0",
+					Firewall:    &v1alpha1.DatabaseFirewall{},
+					DriftPolicy: v1alpha1.DriftPolicyEnforce,
+				},
+			}
+
+			err := k8sClient.Create(ctx, db.DeepCopy())
+			Expect(err).To(MatchError(ContainSubstring("spec.firewall.rules")))
+
+			db.Spec.DriftPolicy = ""
+			Expect(k8sClient.Create(ctx, db)).To(Succeed())
+		})
 	})
 
 	Context("When updating a DatabaseCluster", func() {
@@ -266,4 +406,154 @@ var _ = Describe("DatabaseCluster validating webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("When using a provider config", func() {
+		newDB := func(name, providerConfig string) *v1alpha1.DatabaseCluster {
+			return &v1alpha1.DatabaseCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseClusterKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:            "mysql",
+					Name:              name,
+					Version:           "6",
+					NumNodes:          1,
+					Size:              "db-s-1vcpu-1gb",
+					Region:            "dev0",
+					ProviderConfigRef: &corev1.LocalObjectReference{Name: providerConfig},
+				},
+			}
+		}
+
+		It("should reject a provider config that doesn't exist", func() {
+			err := k8sClient.Create(ctx, newDB("missing-provider-config-db", "no-such-team"))
+			Expect(err).To(MatchError(ContainSubstring("spec.providerConfigRef.name")))
+		})
+
+		It("should accept an existing provider config and reject changing it", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-b-token",
+					Namespace: "default",
+				},
+				StringData: map[string]string{
+					v1alpha1.DefaultProviderConfigTokenKey: "team-b-token",
+				},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			providerConfig := &v1alpha1.DigitalOceanProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-b",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DigitalOceanProviderConfigSpec{
+					CredentialsSecretRef: v1alpha1.ProviderCredentialsSecretReference{
+						Name: secret.Name,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, providerConfig)).To(Succeed())
+
+			db := newDB("team-b-db", "team-b")
+			Eventually(func() error {
+				// The webhook's cache may not have seen the provider config yet.
+				return k8sClient.Create(ctx, db)
+			}, 10*time.Second).Should(Succeed())
+
+			updatedDB := db.DeepCopy()
+			updatedDB.Spec.ProviderConfigRef = nil
+			err := k8sClient.Patch(ctx, updatedDB, client.MergeFrom(db))
+			Expect(err).To(MatchError(ContainSubstring("providerConfigRef is immutable")))
+		})
+	})
+
+	Context("When defaulting a DatabaseCluster", func() {
+		It("should fill in and record the unset fields", func() {
+			db := &v1alpha1.DatabaseCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "defaulted-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine: "mysql",
+					Size:   "db-s-1vcpu-1gb",
+				},
+			}
+			Expect(k8sClient.Create(ctx, db)).To(Succeed())
+
+			Expect(db.Spec.Name).To(Equal("defaulted-db"))
+			Expect(db.Spec.Version).To(Equal("10"))
+			Expect(db.Spec.NumNodes).To(Equal(int64(1)))
+			Expect(db.Spec.Region).To(Equal("dev0"))
+			Expect(db.Annotations).To(HaveKeyWithValue(v1alpha1.DefaultsAnnotation,
+				`{"spec.name":"defaulted-db","spec.numNodes":1,"spec.region":"dev0","spec.version":"10"}`))
+		})
+
+		It("should leave set fields alone", func() {
+			db := &v1alpha1.DatabaseCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "partly-defaulted-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine:   "mysql",
+					Name:     "custom-name",
+					Version:  "6",
+					NumNodes: 2,
+					Size:     "db-s-1vcpu-1gb",
+				},
+			}
+			Expect(k8sClient.Create(ctx, db)).To(Succeed())
+
+			Expect(db.Spec.Name).To(Equal("custom-name"))
+			Expect(db.Spec.Version).To(Equal("6"))
+			Expect(db.Spec.NumNodes).To(Equal(int64(2)))
+			Expect(db.Annotations).To(HaveKeyWithValue(v1alpha1.DefaultsAnnotation, `{"spec.region":"dev0"}`))
+		})
+
+		It("should keep the defaulted fields on an update that leaves them out", func() {
+			db := &v1alpha1.DatabaseCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "reapplied-db",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine: "mysql",
+					Size:   "db-s-1vcpu-1gb",
+				},
+			}
+			Expect(k8sClient.Create(ctx, db)).To(Succeed())
+
+			// Reapply the original manifest with a new size.
+			reapplied := &v1alpha1.DatabaseCluster{
+				ObjectMeta: *db.ObjectMeta.DeepCopy(),
+				Spec: v1alpha1.DatabaseClusterSpec{
+					Engine: "mysql",
+					Size:   "db-s-2vcpu-2gb",
+				},
+			}
+			Expect(k8sClient.Update(ctx, reapplied)).To(Succeed())
+
+			Expect(reapplied.Spec.Name).To(Equal(db.Spec.Name))
+			Expect(reapplied.Spec.Version).To(Equal(db.Spec.Version))
+			Expect(reapplied.Spec.NumNodes).To(Equal(db.Spec.NumNodes))
+			Expect(reapplied.Spec.Region).To(Equal(db.Spec.Region))
+			Expect(reapplied.Spec.Size).To(Equal("db-s-2vcpu-2gb"))
+		})
+	})
+})
+
+var _ = Describe("compareVersions", func() {
+	It("should compare version components numerically", func() {
+		Expect(compareVersions("10", "8")).To(Equal(1))
+		Expect(compareVersions("7.2", "7.10")).To(Equal(-1))
+		Expect(compareVersions("16", "16")).To(Equal(0))
+		Expect(compareVersions("7", "7.2")).To(Equal(-1))
+		Expect(newestVersion([]string{"6", "10", "8"})).To(Equal("10"))
+	})
 })
@@ -22,7 +22,7 @@ import (
 	"net/http"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
-	"github.com/digitalocean/godo"
+	"github.com/digitalocean/do-operator/apitoken"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -32,11 +32,11 @@ import (
 // log is for logging in this package.
 var databaseclusterreferencelog = logf.Log.WithName("databaseclusterreference-resource")
 
-func SetupDatabaseClusterReferenceWebhookWithManager(mgr ctrl.Manager, godoClient *godo.Client) error {
-	initGlobalGodoClient(godoClient)
+func SetupDatabaseClusterReferenceWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
 
 	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseClusterReference{}).
-		WithValidator(&DatabaseClusterReferenceValidator{}).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseClusterReference](&DatabaseClusterReferenceValidator{})).
 		Complete()
 }
 
@@ -47,11 +47,20 @@ type DatabaseClusterReferenceValidator struct{}
 func (v *DatabaseClusterReferenceValidator) ValidateCreate(ctx context.Context, ref *v1alpha1.DatabaseClusterReference) (warnings admission.Warnings, err error) {
 	databaseclusterreferencelog.Info("validate create", "name", ref.Name)
 
+	if err := validateClusterOutput(ref.Spec.Output); err != nil {
+		return warnings, err
+	}
+
+	godoClient, err := godoClientFor(ctx, ref.Namespace, ref.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+
 	dbUUID := ref.Spec.UUID
 	uuidPath := field.NewPath("spec").Child("uuid")
 	_, resp, err := godoClient.Databases.Get(ctx, dbUUID)
 	if err != nil {
-		if resp.StatusCode == http.StatusNotFound {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
 			return warnings, field.Invalid(uuidPath, dbUUID, "database does not exist; you must create it before referencing it")
 		}
 		return warnings, fmt.Errorf("failed to look up database: %v", err)
@@ -68,6 +77,12 @@ func (v *DatabaseClusterReferenceValidator) ValidateUpdate(ctx context.Context,
 	if newRef.Spec.UUID != oldRef.Spec.UUID {
 		return warnings, field.Forbidden(uuidPath, "database UUID is immutable")
 	}
+	if err := validateClusterOutput(newRef.Spec.Output); err != nil {
+		return warnings, err
+	}
+	if err := validateProviderConfigRefUnchanged(oldRef.Spec.ProviderConfigRef, newRef.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
 
 	return warnings, nil
 }
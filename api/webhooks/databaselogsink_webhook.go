@@ -0,0 +1,156 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var databaselogsinklog = logf.Log.WithName("databaselogsink-resource")
+
+func SetupDatabaseLogsinkWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
+	initGlobalK8sClient(mgr.GetClient())
+
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseLogsink{}).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseLogsink](&DatabaseLogsinkValidator{})).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-databases-digitalocean-com-v1alpha1-databaselogsink,mutating=false,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=databaselogsinks,verbs=create;update,versions=v1alpha1,name=vdatabaselogsink.kb.io,admissionReviewVersions=v1
+type DatabaseLogsinkValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseLogsinkValidator) ValidateCreate(ctx context.Context, sink *v1alpha1.DatabaseLogsink) (warnings admission.Warnings, err error) {
+	databaselogsinklog.Info("validate create", "name", sink.Name)
+
+	if err := validateLogsinkConfig(sink); err != nil {
+		return warnings, err
+	}
+
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupReferencedCluster(ctx, sink.Namespace, sink.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
+	}
+	if err := validateClusterProviderConfig(sink.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+
+	if cluster.uuid == "" {
+		// The cluster hasn't been created yet, so the logsink can't exist.
+		return warnings, nil
+	}
+
+	godoClient, err := godoClientFor(ctx, sink.Namespace, sink.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+	existing, _, err := godoClient.Databases.ListLogsinks(ctx, cluster.uuid, nil)
+	if err != nil {
+		return warnings, fmt.Errorf("failed to look up logsinks: %v", err)
+	}
+	for _, s := range existing {
+		if s.Name == sink.Spec.Name {
+			return warnings, field.Duplicate(field.NewPath("spec").Child("name"), sink.Spec.Name)
+		}
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseLogsinkValidator) ValidateUpdate(ctx context.Context, oldSink, newSink *v1alpha1.DatabaseLogsink) (warnings admission.Warnings, err error) {
+	databaselogsinklog.Info("validate update", "name", newSink.Name)
+
+	namePath := field.NewPath("spec").Child("name")
+	if newSink.Spec.Name != oldSink.Spec.Name {
+		return warnings, field.Forbidden(namePath, "logsink name is immutable")
+	}
+	clusterPath := field.NewPath("spec").Child("cluster")
+	if !cmp.Equal(newSink.Spec.Cluster, oldSink.Spec.Cluster) {
+		return warnings, field.Forbidden(clusterPath, "cluster is immutable")
+	}
+	if err := validateLogsinkConfig(newSink); err != nil {
+		return warnings, err
+	}
+	if newSink.Spec.SinkType() != oldSink.Spec.SinkType() {
+		return warnings, field.Forbidden(field.NewPath("spec"), "logsink type is immutable")
+	}
+	if err := validateProviderConfigRefUnchanged(oldSink.Spec.ProviderConfigRef, newSink.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseLogsinkValidator) ValidateDelete(ctx context.Context, sink *v1alpha1.DatabaseLogsink) (warnings admission.Warnings, err error) {
+	databaselogsinklog.Info("validate delete", "name", sink.Name)
+	return warnings, nil
+}
+
+// validateLogsinkConfig checks that exactly one logsink configuration is given
+// and that it comes with the credentials it needs.
+func validateLogsinkConfig(sink *v1alpha1.DatabaseLogsink) error {
+	var (
+		specPath    = field.NewPath("spec")
+		secretPath  = specPath.Child("credentialsSecretRef")
+		configCount = 0
+	)
+
+	for _, set := range []bool{
+		sink.Spec.Rsyslog != nil,
+		sink.Spec.Elasticsearch != nil,
+		sink.Spec.Opensearch != nil,
+		sink.Spec.Datadog != nil,
+	} {
+		if set {
+			configCount++
+		}
+	}
+	switch {
+	case configCount == 0:
+		return field.Required(specPath, "one of rsyslog, elasticsearch, opensearch or datadog must be set")
+	case configCount > 1:
+		return field.Forbidden(specPath, "only one of rsyslog, elasticsearch, opensearch or datadog may be set")
+	}
+
+	switch sink.Spec.SinkType() {
+	case v1alpha1.LogsinkTypeRsyslog:
+		rsyslog := sink.Spec.Rsyslog
+		if rsyslog.Format == "custom" && rsyslog.Logline == "" {
+			return field.Required(specPath.Child("rsyslog").Child("logline"), "logline must be set when using the custom format")
+		}
+	case v1alpha1.LogsinkTypeElasticsearch, v1alpha1.LogsinkTypeOpensearch, v1alpha1.LogsinkTypeDatadog:
+		if sink.Spec.CredentialsSecretRef == nil {
+			return field.Required(secretPath, "credentialsSecretRef must be set for "+sink.Spec.SinkType()+" logsinks")
+		}
+	}
+
+	return nil
+}
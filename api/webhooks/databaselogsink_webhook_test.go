@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+func newRsyslogDatabaseLogsink(name string, cluster *v1alpha1.DatabaseCluster) *v1alpha1.DatabaseLogsink {
+	return &v1alpha1.DatabaseLogsink{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       v1alpha1.DatabaseLogsinkKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.DatabaseLogsinkSpec{
+			Cluster: corev1.TypedLocalObjectReference{
+				APIGroup: &v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.DatabaseClusterKind,
+				Name:     cluster.Name,
+			},
+			Name: name,
+			Rsyslog: &v1alpha1.RsyslogLogsinkConfig{
+				Server: "logs.example.com",
+				Port:   514,
+				Format: "rfc5424",
+			},
+		},
+	}
+}
+
+var _ = Describe("DatabaseLogsink validating webhook", func() {
+	Context("When creating a DatabaseLogsink", func() {
+		It("should reject if the cluster does not exist", func() {
+			sink := newRsyslogDatabaseLogsink("missing-cluster", existingDB)
+			sink.Spec.Cluster.Name = "does-not-exist"
+
+			err := k8sClient.Create(ctx, sink)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject if no configuration is given", func() {
+			sink := newRsyslogDatabaseLogsink("no-config", existingDB)
+			sink.Spec.Rsyslog = nil
+
+			err := k8sClient.Create(ctx, sink)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject if more than one configuration is given", func() {
+			sink := newRsyslogDatabaseLogsink("two-configs", existingDB)
+			sink.Spec.Datadog = &v1alpha1.DatadogLogsinkConfig{Site: "http-intake.logs.datadoghq.com"}
+			sink.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "datadog"}
+
+			err := k8sClient.Create(ctx, sink)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a Datadog logsink without credentials", func() {
+			sink := newRsyslogDatabaseLogsink("datadog-no-credentials", existingDB)
+			sink.Spec.Rsyslog = nil
+			sink.Spec.Datadog = &v1alpha1.DatadogLogsinkConfig{Site: "http-intake.logs.datadoghq.com"}
+
+			err := k8sClient.Create(ctx, sink)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a name already used by a logsink on the cluster", func() {
+			_, _, err := fakeDatabasesService.CreateLogsink(ctx, existingDB.Status.UUID, &godo.DatabaseCreateLogsinkRequest{
+				Name: "taken-logsink",
+				Type: v1alpha1.LogsinkTypeRsyslog,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			sink := newRsyslogDatabaseLogsink("taken-logsink", existingDB)
+
+			err = k8sClient.Create(ctx, sink)
+			Expect(err).To(MatchError(ContainSubstring("Duplicate value")))
+		})
+
+		It("should accept a valid logsink", func() {
+			sink := newRsyslogDatabaseLogsink("valid-logsink", existingDB)
+
+			err := k8sClient.Create(ctx, sink)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When updating a DatabaseLogsink", func() {
+		It("should reject changes to the logsink type", func() {
+			sink := newRsyslogDatabaseLogsink("logsink-to-retype", existingDB)
+			Expect(k8sClient.Create(ctx, sink)).To(Succeed())
+
+			updatedSink := sink.DeepCopy()
+			updatedSink.Spec.Rsyslog = nil
+			updatedSink.Spec.Datadog = &v1alpha1.DatadogLogsinkConfig{Site: "http-intake.logs.datadoghq.com"}
+			updatedSink.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "datadog"}
+			err := k8sClient.Patch(ctx, updatedSink, client.MergeFrom(sink))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow changes to the configuration", func() {
+			sink := newRsyslogDatabaseLogsink("logsink-to-update", existingDB)
+			Expect(k8sClient.Create(ctx, sink)).To(Succeed())
+
+			updatedSink := sink.DeepCopy()
+			updatedSink.Spec.Rsyslog.TLS = true
+			err := k8sClient.Patch(ctx, updatedSink, client.MergeFrom(sink))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
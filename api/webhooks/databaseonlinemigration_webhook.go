@@ -0,0 +1,96 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var databaseonlinemigrationlog = logf.Log.WithName("databaseonlinemigration-resource")
+
+func SetupDatabaseOnlineMigrationWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
+	initGlobalK8sClient(mgr.GetClient())
+
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseOnlineMigration{}).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseOnlineMigration](&DatabaseOnlineMigrationValidator{})).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-databases-digitalocean-com-v1alpha1-databaseonlinemigration,mutating=false,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=databaseonlinemigrations,verbs=create;update,versions=v1alpha1,name=vdatabaseonlinemigration.kb.io,admissionReviewVersions=v1
+type DatabaseOnlineMigrationValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseOnlineMigrationValidator) ValidateCreate(ctx context.Context, migration *v1alpha1.DatabaseOnlineMigration) (warnings admission.Warnings, err error) {
+	databaseonlinemigrationlog.Info("validate create", "name", migration.Name)
+
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupReferencedCluster(ctx, migration.Namespace, migration.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
+	}
+	if err := validateClusterProviderConfig(migration.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+
+	// Only one online migration can run in a cluster at a time, so reject
+	// migrations into a cluster that already has one in progress.
+	var migrations v1alpha1.DatabaseOnlineMigrationList
+	if err := webhookClient.List(ctx, &migrations, client.InNamespace(migration.Namespace)); err != nil {
+		return warnings, fmt.Errorf("failed to list existing migrations: %v", err)
+	}
+	for _, existing := range migrations.Items {
+		if existing.Name == migration.Name || !cmp.Equal(existing.Spec.Cluster, migration.Spec.Cluster) {
+			continue
+		}
+		if !v1alpha1.IsTerminalOnlineMigrationStatus(existing.Status.Status) {
+			return warnings, field.Forbidden(clusterPath, fmt.Sprintf("cluster already has an online migration in progress (%s)", existing.Name))
+		}
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseOnlineMigrationValidator) ValidateUpdate(ctx context.Context, oldMigration, newMigration *v1alpha1.DatabaseOnlineMigration) (warnings admission.Warnings, err error) {
+	databaseonlinemigrationlog.Info("validate update", "name", newMigration.Name)
+
+	// Changing any part of a running migration would require restarting it,
+	// so we require users to create a new object instead.
+	if !cmp.Equal(newMigration.Spec, oldMigration.Spec) {
+		return warnings, field.Forbidden(field.NewPath("spec"), "online migration spec is immutable")
+	}
+
+	return warnings, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *DatabaseOnlineMigrationValidator) ValidateDelete(ctx context.Context, migration *v1alpha1.DatabaseOnlineMigration) (warnings admission.Warnings, err error) {
+	databaseonlinemigrationlog.Info("validate delete", "name", migration.Name)
+	return warnings, nil
+}
@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+func newDatabaseOnlineMigration(name string, cluster *v1alpha1.DatabaseCluster) *v1alpha1.DatabaseOnlineMigration {
+	return &v1alpha1.DatabaseOnlineMigration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       v1alpha1.DatabaseOnlineMigrationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.DatabaseOnlineMigrationSpec{
+			Cluster: corev1.TypedLocalObjectReference{
+				APIGroup: &v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.DatabaseClusterKind,
+				Name:     cluster.Name,
+			},
+			Source: v1alpha1.OnlineMigrationSource{
+				Host: "db.example.com",
+				Port: 5432,
+				CredentialsSecretRef: corev1.LocalObjectReference{
+					Name: "source-credentials",
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("DatabaseOnlineMigration validating webhook", func() {
+	Context("When creating a DatabaseOnlineMigration", func() {
+		It("should reject if the cluster does not exist", func() {
+			migration := newDatabaseOnlineMigration("missing-cluster", existingDB)
+			migration.Spec.Cluster.Name = "does-not-exist"
+
+			err := k8sClient.Create(ctx, migration)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a second migration into the same cluster", func() {
+			cluster := createDatabaseClusterFixture("pg")
+			Expect(k8sClient.Create(ctx, newDatabaseOnlineMigration("first-migration", cluster))).To(Succeed())
+
+			err := k8sClient.Create(ctx, newDatabaseOnlineMigration("second-migration", cluster))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When updating a DatabaseOnlineMigration", func() {
+		It("should reject changes to the spec", func() {
+			migration := newDatabaseOnlineMigration("migration-to-update", existingDB)
+			Expect(k8sClient.Create(ctx, migration)).To(Succeed())
+
+			updatedMigration := migration.DeepCopy()
+			updatedMigration.Spec.IgnoreDBs = []string{"scratch"}
+			err := k8sClient.Patch(ctx, updatedMigration, client.MergeFrom(migration))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
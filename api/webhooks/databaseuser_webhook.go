@@ -18,17 +18,15 @@ package webhooks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
-	"github.com/digitalocean/godo"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/google/go-cmp/cmp"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
-	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -37,12 +35,12 @@ import (
 // log is for logging in this package.
 var databaseuserlog = logf.Log.WithName("databaseuser-resource")
 
-func SetupDatabaseUserWebhookWithManager(mgr ctrl.Manager, godoClient *godo.Client) error {
-	initGlobalGodoClient(godoClient)
+func SetupDatabaseUserWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
 	initGlobalK8sClient(mgr.GetClient())
 
 	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseUser{}).
-		WithValidator(&DatabaseUserValidator{}).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseUser](&DatabaseUserValidator{})).
 		Complete()
 }
 
@@ -53,51 +51,35 @@ type DatabaseUserValidator struct{}
 func (v *DatabaseUserValidator) ValidateCreate(ctx context.Context, user *v1alpha1.DatabaseUser) (warnings admission.Warnings, err error) {
 	databaseuserlog.Info("validate create", "name", user.Name)
 
-	clusterPath := field.NewPath("spec").Child("cluster")
-
-	clusterAPIGroup := pointer.StringDeref(user.Spec.Cluster.APIGroup, "")
-	if clusterAPIGroup != v1alpha1.GroupVersion.Group {
-		return warnings, field.Invalid(clusterPath.Child("apiGroup"), clusterAPIGroup, "apiGroup must be "+v1alpha1.GroupVersion.Group)
+	if err := validatePasswordRotation(user.Spec.PasswordRotation); err != nil {
+		return warnings, err
+	}
+	if err := validateSecretTemplate(user.Spec.SecretTemplate); err != nil {
+		return warnings, err
+	}
+	if err := validateUserOutput(user.Spec.Output); err != nil {
+		return warnings, err
 	}
 
-	var (
-		clusterNN = types.NamespacedName{
-			Namespace: user.Namespace,
-			Name:      user.Spec.Cluster.Name,
-		}
-		clusterKind = user.Spec.Cluster.Kind
-		clusterUUID string
-	)
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupGrantedCluster(ctx, user.Namespace, user.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
+	}
 
-	switch strings.ToLower(clusterKind) {
-	case strings.ToLower(v1alpha1.DatabaseClusterKind):
-		var cluster v1alpha1.DatabaseCluster
-		if err := webhookClient.Get(ctx, clusterNN, &cluster); err != nil {
-			if kerrors.IsNotFound(err) {
-				return warnings, field.NotFound(clusterPath, clusterNN)
-			}
-			return warnings, fmt.Errorf("failed to fetch DatabaseCluster %s: %s", clusterNN, err)
-		}
-		clusterUUID = cluster.Status.UUID
-	case strings.ToLower(v1alpha1.DatabaseClusterReferenceKind):
-		var clusterRef v1alpha1.DatabaseClusterReference
-		if err := webhookClient.Get(ctx, clusterNN, &clusterRef); err != nil {
-			if kerrors.IsNotFound(err) {
-				return warnings, field.NotFound(clusterPath, clusterNN)
-			}
-			return warnings, fmt.Errorf("failed to fetch DatabaseClusterReference %s: %s", clusterNN, err)
-		}
-		clusterUUID = clusterRef.Spec.UUID
-	default:
-		return warnings, field.TypeInvalid(
-			clusterPath.Child("kind"),
-			clusterKind,
-			"kind must be DatabaseCluster or DatabaseClusterReference",
-		)
+	if err := validateClusterProviderConfig(user.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+	if err := validateUserEngineSettings(&user.Spec, cluster.engine); err != nil {
+		return warnings, err
 	}
 
-	_, resp, err := godoClient.Databases.GetUser(ctx, clusterUUID, user.Spec.Username)
-	if err != nil && resp.StatusCode != http.StatusNotFound {
+	godoClient, err := godoClientFor(ctx, user.Namespace, user.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+	_, resp, err := godoClient.Databases.GetUser(ctx, cluster.uuid, user.Spec.Username)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
 		return warnings, fmt.Errorf("failed to look up database: %v", err)
 	}
 	if err == nil {
@@ -119,6 +101,35 @@ func (v *DatabaseUserValidator) ValidateUpdate(ctx context.Context, oldUser, new
 	if !cmp.Equal(newUser.Spec.Cluster, oldUser.Spec.Cluster) {
 		return warnings, field.Forbidden(clusterPath, "cluster is immutable")
 	}
+	if err := validatePasswordRotation(newUser.Spec.PasswordRotation); err != nil {
+		return warnings, err
+	}
+	if err := validateSecretTemplate(newUser.Spec.SecretTemplate); err != nil {
+		return warnings, err
+	}
+	if err := validateUserOutput(newUser.Spec.Output); err != nil {
+		return warnings, err
+	}
+	if err := validateProviderConfigRefUnchanged(oldUser.Spec.ProviderConfigRef, newUser.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
+
+	authPluginChanged := newUser.Spec.MySQLAuthPlugin != oldUser.Spec.MySQLAuthPlugin
+	engineSettingsChanged := !cmp.Equal(newUser.Spec.ACL, oldUser.Spec.ACL) ||
+		!cmp.Equal(newUser.Spec.OpenSearch, oldUser.Spec.OpenSearch) ||
+		!cmp.Equal(newUser.Spec.MongoDB, oldUser.Spec.MongoDB)
+	if authPluginChanged || engineSettingsChanged {
+		cluster, err := lookupGrantedCluster(ctx, newUser.Namespace, newUser.Spec.Cluster, clusterPath)
+		if err != nil {
+			return warnings, err
+		}
+		if err := validateUserEngineSettings(&newUser.Spec, cluster.engine); err != nil {
+			return warnings, err
+		}
+	}
+	if authPluginChanged {
+		warnings = append(warnings, "changing the MySQL auth plugin resets the user's password")
+	}
 
 	return warnings, nil
 }
@@ -128,3 +139,57 @@ func (v *DatabaseUserValidator) ValidateDelete(ctx context.Context, user *v1alph
 	databaseuserlog.Info("validate delete", "name", user.Name)
 	return warnings, nil
 }
+
+// validateUserEngineSettings checks that the user doesn't set any
+// engine-specific settings that the cluster's engine doesn't support.
+func validateUserEngineSettings(spec *v1alpha1.DatabaseUserSpec, engine string) error {
+	type engineSetting struct {
+		path   *field.Path
+		value  any
+		engine string
+	}
+
+	var (
+		specPath = field.NewPath("spec")
+		settings []engineSetting
+	)
+	if spec.MySQLAuthPlugin != "" {
+		settings = append(settings, engineSetting{specPath.Child("mysqlAuthPlugin"), spec.MySQLAuthPlugin, "mysql"})
+	}
+	if len(spec.ACL) > 0 {
+		settings = append(settings, engineSetting{specPath.Child("acl"), spec.ACL, "kafka"})
+	}
+	if spec.OpenSearch != nil {
+		settings = append(settings, engineSetting{specPath.Child("opensearch"), spec.OpenSearch, "opensearch"})
+	}
+	if spec.MongoDB != nil {
+		settings = append(settings, engineSetting{specPath.Child("mongodb"), spec.MongoDB, "mongodb"})
+	}
+
+	for _, setting := range settings {
+		switch engine {
+		case setting.engine:
+		case "":
+			// This is most likely for DatabaseClusterReferences that haven't
+			// been reconciled yet.
+			return errors.New("could not determine database engine")
+		default:
+			return field.Invalid(setting.path, setting.value, fmt.Sprintf("only supported for %s databases", setting.engine))
+		}
+	}
+
+	return nil
+}
+
+// validatePasswordRotation checks that passwords aren't rotated so often that
+// workloads can't keep up.
+func validatePasswordRotation(rotation *v1alpha1.PasswordRotation) error {
+	if rotation == nil || rotation.Interval == nil {
+		return nil
+	}
+	if rotation.Interval.Duration < time.Hour {
+		intervalPath := field.NewPath("spec").Child("passwordRotation").Child("interval")
+		return field.Invalid(intervalPath, rotation.Interval.Duration.String(), "rotation interval must be at least one hour")
+	}
+	return nil
+}
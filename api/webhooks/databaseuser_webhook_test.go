@@ -1,6 +1,8 @@
 package webhooks
 
 import (
+	"time"
+
 	"github.com/digitalocean/godo"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -10,6 +12,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/api/v1beta1"
 )
 
 var (
@@ -98,7 +101,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: pointer.String("does.not.exist"),
 					},
 				},
@@ -119,7 +122,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     "DoesNotExist",
 					},
@@ -141,7 +144,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     "does-not-exist",
@@ -164,7 +167,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     "my-cluster-ref",
@@ -187,7 +190,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -211,7 +214,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     existingDBRef.Name,
@@ -235,7 +238,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -248,6 +251,31 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should reject a provider config that doesn't match the cluster's", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-team-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username:          "other-team-user",
+					ProviderConfigRef: &corev1.LocalObjectReference{Name: "other-team"},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(MatchError(ContainSubstring("must match the referenced cluster's provider config")))
+		})
+
 		It("should accept a new DatabaseClusterReference user", func() {
 			dbUser := &v1alpha1.DatabaseUser{
 				TypeMeta: metav1.TypeMeta{
@@ -259,7 +287,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     existingDBRef.Name,
@@ -273,6 +301,480 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 		})
 	})
 
+	Context("When referencing a cluster in another namespace", func() {
+		It("should require a DatabaseClusterGrant in the cluster's namespace", func() {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-team"},
+			}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cross-namespace-user",
+					Namespace: ns.Name,
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup:  &v1alpha1.GroupVersion.Group,
+						Kind:      v1alpha1.DatabaseClusterKind,
+						Name:      existingDB.Name,
+						Namespace: existingDB.Namespace,
+					},
+					Username: "cross-namespace-user",
+				},
+			}
+			err := k8sClient.Create(ctx, dbUser.DeepCopy())
+			Expect(err).To(MatchError(ContainSubstring("no DatabaseClusterGrant")))
+
+			grant := &v1alpha1.DatabaseClusterGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "app-team",
+					Namespace: existingDB.Namespace,
+				},
+				Spec: v1alpha1.DatabaseClusterGrantSpec{
+					Namespaces: []string{ns.Name},
+					Clusters: []v1alpha1.GrantedCluster{{
+						Kind: v1alpha1.DatabaseClusterKind,
+						Name: existingDB.Name,
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+
+			Eventually(func() error {
+				// The webhook's cache may not have seen the grant yet.
+				return k8sClient.Create(ctx, dbUser.DeepCopy())
+			}, 10*time.Second).Should(Succeed())
+		})
+	})
+
+	Context("When setting the MySQL auth plugin", func() {
+		It("should reject the auth plugin for a non-MySQL cluster", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kafka-native-password-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingKafkaDB.Name,
+					},
+					Username:        "kafka-native-password-user",
+					MySQLAuthPlugin: godo.SQLAuthPluginNative,
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept the auth plugin for a MySQL cluster and allow changing it", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mysql-native-password-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username:        "mysql-native-password-user",
+					MySQLAuthPlugin: godo.SQLAuthPluginNative,
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedUser := dbUser.DeepCopy()
+			updatedUser.Spec.MySQLAuthPlugin = godo.SQLAuthPluginCachingSHA2
+			err = k8sClient.Patch(ctx, updatedUser, client.MergeFrom(dbUser))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When setting Kafka ACLs", func() {
+		It("should reject ACLs for a non-Kafka cluster", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mysql-acl-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "mysql-acl-user",
+					ACL: []v1alpha1.KafkaACL{{
+						Topic:      "orders",
+						Permission: "consume",
+					}},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept ACLs for a Kafka cluster and allow changing them", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kafka-acl-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingKafkaDB.Name,
+					},
+					Username: "kafka-acl-user",
+					ACL: []v1alpha1.KafkaACL{{
+						Topic:      "orders",
+						Permission: "consume",
+					}},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).NotTo(HaveOccurred())
+
+			updatedUser := dbUser.DeepCopy()
+			updatedUser.Spec.ACL = append(updatedUser.Spec.ACL, v1alpha1.KafkaACL{
+				Topic:      "events.*",
+				Permission: "produceconsume",
+			})
+			err = k8sClient.Patch(ctx, updatedUser, client.MergeFrom(dbUser))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When setting OpenSearch and MongoDB settings", func() {
+		It("should reject MongoDB settings for a non-MongoDB cluster", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mysql-mongodb-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "mysql-mongodb-user",
+					MongoDB: &v1alpha1.MongoDBUserSettings{
+						Databases: []string{"orders"},
+						Role:      "readOnly",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept OpenSearch settings for an OpenSearch cluster", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "opensearch-acl-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingOpenSearchDB.Name,
+					},
+					Username: "opensearch-acl-user",
+					OpenSearch: &v1alpha1.OpenSearchUserSettings{
+						ACL: []v1alpha1.OpenSearchACL{{
+							Index:      "logs-*",
+							Permission: "read",
+						}},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When configuring password rotation", func() {
+		It("should reject a rotation interval under an hour", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fast-rotation-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "fast-rotation-user",
+					PasswordRotation: &v1alpha1.PasswordRotation{
+						Interval: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When setting a secret template", func() {
+		It("should reject a template that doesn't parse", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bad-template-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "bad-template-user",
+					SecretTemplate: map[string]string{
+						"DATABASE_URL": "mysql://{{ .User }:{{ .Password }}@{{ .Host }}",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a template that references an unknown field", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unknown-field-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "unknown-field-user",
+					SecretTemplate: map[string]string{
+						"DATABASE_URL": "mysql://{{ .Username }}@{{ .Host }}",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a template for a Service Binding key", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "binding-key-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "binding-key-user",
+					SecretTemplate: map[string]string{
+						"type": "postgresql",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(MatchError(ContainSubstring("spec.secretTemplate[type]")))
+		})
+
+		It("should accept a valid template", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "templated-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "templated-user",
+					SecretTemplate: map[string]string{
+						"SPRING_DATASOURCE_URL": "jdbc:mysql://{{ .Host }}:{{ .Port }}/{{ .Database }}?sslMode={{ .SSLMode }}",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When configuring the output", func() {
+		It("should reject an invalid Secret name", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bad-output-name-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "bad-output-name-user",
+					Output: &v1alpha1.UserOutput{
+						CredentialsSecretName: "Not_A_Name",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject overriding the managed-by label", func() {
+			dbUser := &v1alpha1.DatabaseUser{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       v1alpha1.DatabaseUserKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed-by-user",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster: v1alpha1.ClusterReference{
+						APIGroup: &v1alpha1.GroupVersion.Group,
+						Kind:     v1alpha1.DatabaseClusterKind,
+						Name:     existingDB.Name,
+					},
+					Username: "managed-by-user",
+					Output: &v1alpha1.UserOutput{
+						OutputMetadata: v1alpha1.OutputMetadata{
+							Labels: map[string]string{v1alpha1.ManagedByLabel: "someone-else"},
+						},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, dbUser)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When using the v1beta1 API", func() {
+		It("should validate v1beta1 users and serve them as v1alpha1", func() {
+			newUser := func(name, template string) *v1beta1.DatabaseUser {
+				return &v1beta1.DatabaseUser{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1beta1.GroupVersion.String(),
+						Kind:       v1alpha1.DatabaseUserKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+					},
+					Spec: v1beta1.DatabaseUserSpec{
+						ClusterRef: v1beta1.ClusterReference{
+							Kind: v1alpha1.DatabaseClusterKind,
+							Name: existingDB.Name,
+						},
+						Username: name,
+						Output: &v1beta1.DatabaseUserOutput{
+							SecretTemplate: map[string]string{"DATABASE_URL": template},
+						},
+					},
+				}
+			}
+
+			err := k8sClient.Create(ctx, newUser("v1beta1-bad-template-user", "mysql://{{ .Username }}@{{ .Host }}"))
+			Expect(err).To(HaveOccurred())
+
+			dbUser := newUser("v1beta1-user", "mysql://{{ .User }}@{{ .Host }}")
+			Expect(k8sClient.Create(ctx, dbUser)).To(Succeed())
+
+			var got v1alpha1.DatabaseUser
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(dbUser), &got)).To(Succeed())
+			Expect(got.Spec.Cluster).To(Equal(v1alpha1.ClusterReference{
+				APIGroup: &v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.DatabaseClusterKind,
+				Name:     existingDB.Name,
+			}))
+			Expect(got.Spec.Output).To(BeNil())
+			Expect(got.Spec.SecretTemplate).To(Equal(dbUser.Spec.Output.SecretTemplate))
+		})
+	})
+
 	Context("When updating a DatabaseUser", func() {
 		It("should reject changes to the cluster", func() {
 			dbUser := &v1alpha1.DatabaseUser{
@@ -285,7 +787,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -314,7 +816,7 @@ var _ = Describe("DatabaseUser validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -21,30 +21,26 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
-	"github.com/digitalocean/godo"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/google/go-cmp/cmp"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
-	"k8s.io/utils/pointer"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // log is for logging in this package.
 var databaseuserreferencelog = logf.Log.WithName("databaseuserreference-resource")
 
-func SetupDatabaseUserReferenceWebhookWithManager(mgr ctrl.Manager, godoClient *godo.Client) error {
-	initGlobalGodoClient(godoClient)
+func SetupDatabaseUserReferenceWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
 	initGlobalK8sClient(mgr.GetClient())
 
 	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.DatabaseUserReference{}).
-		WithValidator(&DatabaseUserReferenceValidator{}).
+		WithValidator(watchedOnly[*v1alpha1.DatabaseUserReference](&DatabaseUserReferenceValidator{})).
 		Complete()
 }
 
@@ -55,53 +51,17 @@ type DatabaseUserReferenceValidator struct{}
 func (v *DatabaseUserReferenceValidator) ValidateCreate(ctx context.Context, ref *v1alpha1.DatabaseUserReference) (warnings admission.Warnings, err error) {
 	databaseuserreferencelog.Info("validate create", "name", ref.Name)
 
-	clusterPath := field.NewPath("spec").Child("cluster")
-
-	clusterAPIGroup := pointer.StringDeref(ref.Spec.Cluster.APIGroup, "")
-	if clusterAPIGroup != v1alpha1.GroupVersion.Group {
-		return warnings, field.Invalid(clusterPath.Child("apiGroup"), clusterAPIGroup, "apiGroup must be "+v1alpha1.GroupVersion.Group)
+	if err := validateUserOutput(ref.Spec.Output); err != nil {
+		return warnings, err
 	}
 
-	var (
-		clusterNN = types.NamespacedName{
-			Namespace: ref.Namespace,
-			Name:      ref.Spec.Cluster.Name,
-		}
-		clusterKind = ref.Spec.Cluster.Kind
-		clusterUUID string
-		engine      string
-	)
-
-	switch strings.ToLower(clusterKind) {
-	case strings.ToLower(v1alpha1.DatabaseClusterKind):
-		var cluster v1alpha1.DatabaseCluster
-		if err := webhookClient.Get(ctx, clusterNN, &cluster); err != nil {
-			if kerrors.IsNotFound(err) {
-				return warnings, field.NotFound(clusterPath, clusterNN)
-			}
-			return warnings, fmt.Errorf("failed to fetch DatabaseCluster %s: %s", clusterNN, err)
-		}
-		clusterUUID = cluster.Status.UUID
-		engine = cluster.Spec.Engine
-	case strings.ToLower(v1alpha1.DatabaseClusterReferenceKind):
-		var clusterRef v1alpha1.DatabaseClusterReference
-		if err := webhookClient.Get(ctx, clusterNN, &clusterRef); err != nil {
-			if kerrors.IsNotFound(err) {
-				return warnings, field.NotFound(clusterPath, clusterNN)
-			}
-			return warnings, fmt.Errorf("failed to fetch DatabaseClusterReference %s: %s", clusterNN, err)
-		}
-		clusterUUID = clusterRef.Spec.UUID
-		engine = clusterRef.Status.Engine
-	default:
-		return warnings, field.TypeInvalid(
-			clusterPath.Child("kind"),
-			clusterKind,
-			"kind must be DatabaseCluster or DatabaseClusterReference",
-		)
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupGrantedCluster(ctx, ref.Namespace, ref.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
 	}
 
-	switch engine {
+	switch cluster.engine {
 	case "":
 		// This is most likely for DatabaseClusterReferences that haven't been
 		// reconciled yet.
@@ -112,9 +72,17 @@ func (v *DatabaseUserReferenceValidator) ValidateCreate(ctx context.Context, ref
 		return warnings, field.Invalid(clusterPath, ref.Spec.Cluster, "user management is not supported for Redis databases")
 	}
 
-	_, resp, err := godoClient.Databases.GetUser(ctx, clusterUUID, ref.Spec.Username)
+	if err := validateClusterProviderConfig(ref.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+
+	godoClient, err := godoClientFor(ctx, ref.Namespace, ref.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+	_, resp, err := godoClient.Databases.GetUser(ctx, cluster.uuid, ref.Spec.Username)
 	if err != nil {
-		if resp.StatusCode == http.StatusNotFound {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
 			return warnings, field.Invalid(
 				field.NewPath("spec").Child("username"),
 				ref.Spec.Username,
@@ -139,6 +107,12 @@ func (v *DatabaseUserReferenceValidator) ValidateUpdate(ctx context.Context, old
 	if !cmp.Equal(newRef.Spec.Cluster, oldRef.Spec.Cluster) {
 		return warnings, field.Forbidden(clusterPath, "cluster is immutable")
 	}
+	if err := validateUserOutput(newRef.Spec.Output); err != nil {
+		return warnings, err
+	}
+	if err := validateProviderConfigRefUnchanged(oldRef.Spec.ProviderConfigRef, newRef.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
 
 	return warnings, nil
 }
@@ -4,7 +4,6 @@ import (
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,7 +22,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: pointer.String("does.not.exist"),
 					},
 				},
@@ -44,7 +43,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     "DoesNotExist",
 					},
@@ -66,7 +65,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     "does-not-exist",
@@ -89,7 +88,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     "my-cluster-ref",
@@ -114,7 +113,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     db.Name,
@@ -139,7 +138,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     db.Name,
@@ -162,7 +161,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -186,7 +185,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     existingDBRef.Name,
@@ -210,7 +209,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -234,7 +233,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterReferenceKind,
 						Name:     existingDBRef.Name,
@@ -261,7 +260,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -290,7 +289,7 @@ var _ = Describe("DatabaseUserReference validating webhook", func() {
 					Namespace: "default",
 				},
 				Spec: v1alpha1.DatabaseUserReferenceSpec{
-					Cluster: corev1.TypedLocalObjectReference{
+					Cluster: v1alpha1.ClusterReference{
 						APIGroup: &v1alpha1.GroupVersion.Group,
 						Kind:     v1alpha1.DatabaseClusterKind,
 						Name:     existingDB.Name,
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var kafkaschemasubjectlog = logf.Log.WithName("kafkaschemasubject-resource")
+
+func SetupKafkaSchemaSubjectWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
+	initGlobalK8sClient(mgr.GetClient())
+
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.KafkaSchemaSubject{}).
+		WithValidator(watchedOnly[*v1alpha1.KafkaSchemaSubject](&KafkaSchemaSubjectValidator{})).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-databases-digitalocean-com-v1alpha1-kafkaschemasubject,mutating=false,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=kafkaschemasubjects,verbs=create;update,versions=v1alpha1,name=vkafkaschemasubject.kb.io,admissionReviewVersions=v1
+type KafkaSchemaSubjectValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *KafkaSchemaSubjectValidator) ValidateCreate(ctx context.Context, subject *v1alpha1.KafkaSchemaSubject) (warnings admission.Warnings, err error) {
+	kafkaschemasubjectlog.Info("validate create", "name", subject.Name)
+
+	if err := validateSchemaSource(subject); err != nil {
+		return warnings, err
+	}
+
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupReferencedCluster(ctx, subject.Namespace, subject.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
+	}
+	if err := validateClusterProviderConfig(subject.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+
+	switch cluster.engine {
+	case "":
+		// This is most likely for DatabaseClusterReferences that haven't been
+		// reconciled yet.
+		return warnings, errors.New("could not determine database engine")
+	case "kafka":
+	default:
+		return warnings, field.Invalid(clusterPath, subject.Spec.Cluster, "schema registry subjects are only supported for Kafka databases")
+	}
+
+	if cluster.uuid == "" {
+		// The cluster hasn't been created yet, so the subject can't exist.
+		return warnings, nil
+	}
+
+	godoClient, err := godoClientFor(ctx, subject.Namespace, subject.Spec.ProviderConfigRef)
+	if err != nil {
+		return warnings, err
+	}
+	_, resp, err := godoClient.Databases.GetKafkaSchemaRegistry(ctx, cluster.uuid, subject.Spec.SubjectName)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return warnings, fmt.Errorf("failed to look up schema subject: %v", err)
+	}
+	if err == nil {
+		return warnings, field.Duplicate(field.NewPath("spec").Child("subjectName"), subject.Spec.SubjectName)
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *KafkaSchemaSubjectValidator) ValidateUpdate(ctx context.Context, oldSubject, newSubject *v1alpha1.KafkaSchemaSubject) (warnings admission.Warnings, err error) {
+	kafkaschemasubjectlog.Info("validate update", "name", newSubject.Name)
+
+	subjectNamePath := field.NewPath("spec").Child("subjectName")
+	if newSubject.Spec.SubjectName != oldSubject.Spec.SubjectName {
+		return warnings, field.Forbidden(subjectNamePath, "subject name is immutable")
+	}
+	schemaTypePath := field.NewPath("spec").Child("schemaType")
+	if newSubject.Spec.SchemaType != oldSubject.Spec.SchemaType {
+		return warnings, field.Forbidden(schemaTypePath, "schema type is immutable")
+	}
+	clusterPath := field.NewPath("spec").Child("cluster")
+	if !cmp.Equal(newSubject.Spec.Cluster, oldSubject.Spec.Cluster) {
+		return warnings, field.Forbidden(clusterPath, "cluster is immutable")
+	}
+	if err := validateProviderConfigRefUnchanged(oldSubject.Spec.ProviderConfigRef, newSubject.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
+
+	return warnings, validateSchemaSource(newSubject)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *KafkaSchemaSubjectValidator) ValidateDelete(ctx context.Context, subject *v1alpha1.KafkaSchemaSubject) (warnings admission.Warnings, err error) {
+	kafkaschemasubjectlog.Info("validate delete", "name", subject.Name)
+	return warnings, nil
+}
+
+// validateSchemaSource checks that exactly one source is given for the schema
+// definition.
+func validateSchemaSource(subject *v1alpha1.KafkaSchemaSubject) error {
+	var (
+		schemaPath    = field.NewPath("spec").Child("schema")
+		configMapPath = field.NewPath("spec").Child("schemaConfigMapRef")
+	)
+
+	switch {
+	case subject.Spec.Schema == "" && subject.Spec.SchemaConfigMapRef == nil:
+		return field.Required(schemaPath, "one of schema or schemaConfigMapRef must be set")
+	case subject.Spec.Schema != "" && subject.Spec.SchemaConfigMapRef != nil:
+		return field.Forbidden(configMapPath, "schemaConfigMapRef may not be set when schema is set")
+	case subject.Spec.SchemaConfigMapRef != nil && subject.Spec.SchemaConfigMapRef.Key == "":
+		return field.Required(configMapPath.Child("key"), "key must be set")
+	}
+
+	return nil
+}
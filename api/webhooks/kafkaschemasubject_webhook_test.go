@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var (
+	existingKafkaDB       *v1alpha1.DatabaseCluster
+	existingSubjectName   = "existing-subject"
+	kafkaSchemaDefinition = `{"type":"string"}`
+)
+
+func createKafkaSchemaSubjectWebhookTestFixtures() {
+	existingKafkaDB = createDatabaseClusterFixture("kafka")
+
+	// Register a subject in the DB to test duplicate names.
+	_, _, err := fakeDatabasesService.CreateKafkaSchemaRegistry(ctx, existingKafkaDB.Status.UUID, &godo.DatabaseKafkaSchemaRegistryRequest{
+		SubjectName: existingSubjectName,
+		SchemaType:  "AVRO",
+		Schema:      kafkaSchemaDefinition,
+	})
+	Expect(err).NotTo(HaveOccurred())
+}
+
+func newKafkaSchemaSubject(name string, cluster *v1alpha1.DatabaseCluster) *v1alpha1.KafkaSchemaSubject {
+	return &v1alpha1.KafkaSchemaSubject{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       v1alpha1.KafkaSchemaSubjectKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.KafkaSchemaSubjectSpec{
+			Cluster: corev1.TypedLocalObjectReference{
+				APIGroup: &v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.DatabaseClusterKind,
+				Name:     cluster.Name,
+			},
+			SubjectName: name,
+			SchemaType:  "AVRO",
+			Schema:      kafkaSchemaDefinition,
+		},
+	}
+}
+
+var _ = Describe("KafkaSchemaSubject validating webhook", func() {
+	Context("When creating a KafkaSchemaSubject", func() {
+		It("should reject if the cluster is not a Kafka cluster", func() {
+			subject := newKafkaSchemaSubject("not-kafka", existingDB)
+
+			err := k8sClient.Create(ctx, subject)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject if no schema is given", func() {
+			subject := newKafkaSchemaSubject("no-schema", existingKafkaDB)
+			subject.Spec.Schema = ""
+
+			err := k8sClient.Create(ctx, subject)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject if both an inline schema and a ConfigMap are given", func() {
+			subject := newKafkaSchemaSubject("two-schemas", existingKafkaDB)
+			subject.Spec.SchemaConfigMapRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"},
+				Key:                  "schema.avsc",
+			}
+
+			err := k8sClient.Create(ctx, subject)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject if the subject already exists", func() {
+			subject := newKafkaSchemaSubject(existingSubjectName, existingKafkaDB)
+
+			err := k8sClient.Create(ctx, subject)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept a new subject", func() {
+			subject := newKafkaSchemaSubject("new-subject", existingKafkaDB)
+
+			err := k8sClient.Create(ctx, subject)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When updating a KafkaSchemaSubject", func() {
+		It("should reject changes to the subject name", func() {
+			subject := newKafkaSchemaSubject("subject-to-rename", existingKafkaDB)
+			Expect(k8sClient.Create(ctx, subject)).To(Succeed())
+
+			updatedSubject := subject.DeepCopy()
+			updatedSubject.Spec.SubjectName = "different-name"
+			err := k8sClient.Patch(ctx, updatedSubject, client.MergeFrom(subject))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow changes to the schema", func() {
+			subject := newKafkaSchemaSubject("subject-to-evolve", existingKafkaDB)
+			Expect(k8sClient.Create(ctx, subject)).To(Succeed())
+
+			updatedSubject := subject.DeepCopy()
+			updatedSubject.Spec.Schema = `{"type":"long"}`
+			err := k8sClient.Patch(ctx, updatedSubject, client.MergeFrom(subject))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
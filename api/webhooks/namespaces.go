@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// watchedNamespaces are the namespaces whose objects the webhooks validate. A
+// nil set means every namespace. It's only written before the webhooks are
+// set up, so it's safe to read without locking.
+var watchedNamespaces map[string]bool
+
+// SetWatchNamespaces restricts the webhooks to objects in the given
+// namespaces, which should match the namespaces the manager's cache is
+// restricted to. Objects in other namespaces are admitted without validation,
+// since they're reconciled by another operator installation, if any, and
+// can't be looked up through the cache. It must be called before the webhooks
+// are set up.
+func SetWatchNamespaces(namespaces []string) {
+	if len(namespaces) == 0 {
+		watchedNamespaces = nil
+		return
+	}
+	watchedNamespaces = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		watchedNamespaces[ns] = true
+	}
+}
+
+// isWatchedNamespace returns whether the webhooks validate objects in
+// namespace.
+func isWatchedNamespace(namespace string) bool {
+	return watchedNamespaces == nil || watchedNamespaces[namespace]
+}
+
+// watchedOnly wraps a validator so that it only validates objects in the
+// watched namespaces.
+func watchedOnly[T client.Object](validator admission.Validator[T]) admission.Validator[T] {
+	return &namespaceFilter[T]{validator: validator}
+}
+
+type namespaceFilter[T client.Object] struct {
+	validator admission.Validator[T]
+}
+
+func (f *namespaceFilter[T]) ValidateCreate(ctx context.Context, obj T) (admission.Warnings, error) {
+	if !isWatchedNamespace(obj.GetNamespace()) {
+		return nil, nil
+	}
+	return f.validator.ValidateCreate(ctx, obj)
+}
+
+func (f *namespaceFilter[T]) ValidateUpdate(ctx context.Context, oldObj, newObj T) (admission.Warnings, error) {
+	if !isWatchedNamespace(newObj.GetNamespace()) {
+		return nil, nil
+	}
+	return f.validator.ValidateUpdate(ctx, oldObj, newObj)
+}
+
+func (f *namespaceFilter[T]) ValidateDelete(ctx context.Context, obj T) (admission.Warnings, error) {
+	if !isWatchedNamespace(obj.GetNamespace()) {
+		return nil, nil
+	}
+	return f.validator.ValidateDelete(ctx, obj)
+}
+
+// watchedOnlyDefaulter wraps a defaulter so that it only defaults objects in
+// the watched namespaces.
+func watchedOnlyDefaulter[T client.Object](defaulter admission.Defaulter[T]) admission.Defaulter[T] {
+	return &namespaceDefaulterFilter[T]{defaulter: defaulter}
+}
+
+type namespaceDefaulterFilter[T client.Object] struct {
+	defaulter admission.Defaulter[T]
+}
+
+func (f *namespaceDefaulterFilter[T]) Default(ctx context.Context, obj T) error {
+	if !isWatchedNamespace(obj.GetNamespace()) {
+		return nil
+	}
+	return f.defaulter.Default(ctx, obj)
+}
@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// rejectingValidator rejects every object.
+type rejectingValidator struct{}
+
+var errRejected = errors.New("rejected")
+
+func (rejectingValidator) ValidateCreate(context.Context, *v1alpha1.DatabaseUser) (admission.Warnings, error) {
+	return nil, errRejected
+}
+
+func (rejectingValidator) ValidateUpdate(context.Context, *v1alpha1.DatabaseUser, *v1alpha1.DatabaseUser) (admission.Warnings, error) {
+	return nil, errRejected
+}
+
+func (rejectingValidator) ValidateDelete(context.Context, *v1alpha1.DatabaseUser) (admission.Warnings, error) {
+	return nil, errRejected
+}
+
+var _ = Describe("Watched namespaces", func() {
+	userIn := func(namespace string) *v1alpha1.DatabaseUser {
+		return &v1alpha1.DatabaseUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "user", Namespace: namespace},
+		}
+	}
+
+	AfterEach(func() {
+		SetWatchNamespaces(nil)
+	})
+
+	It("should validate objects in every namespace by default", func() {
+		validator := watchedOnly[*v1alpha1.DatabaseUser](rejectingValidator{})
+		_, err := validator.ValidateCreate(ctx, userIn("team-a"))
+		Expect(err).To(MatchError(errRejected))
+	})
+
+	It("should only validate objects in the watched namespaces", func() {
+		SetWatchNamespaces([]string{"team-a", "team-b"})
+		validator := watchedOnly[*v1alpha1.DatabaseUser](rejectingValidator{})
+
+		_, err := validator.ValidateCreate(ctx, userIn("team-b"))
+		Expect(err).To(MatchError(errRejected))
+		_, err = validator.ValidateUpdate(ctx, userIn("team-a"), userIn("team-a"))
+		Expect(err).To(MatchError(errRejected))
+
+		_, err = validator.ValidateCreate(ctx, userIn("team-c"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = validator.ValidateUpdate(ctx, userIn("team-c"), userIn("team-c"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = validator.ValidateDelete(ctx, userIn("team-c"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"path"
+	"time"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var opensearchindexpolicylog = logf.Log.WithName("opensearchindexpolicy-resource")
+
+func SetupOpenSearchIndexPolicyWebhookWithManager(mgr ctrl.Manager, godoClients *apitoken.ClientCache) error {
+	initGlobalGodoClients(godoClients)
+	initGlobalK8sClient(mgr.GetClient())
+
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.OpenSearchIndexPolicy{}).
+		WithValidator(watchedOnly[*v1alpha1.OpenSearchIndexPolicy](&OpenSearchIndexPolicyValidator{})).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-databases-digitalocean-com-v1alpha1-opensearchindexpolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=databases.digitalocean.com,resources=opensearchindexpolicies,verbs=create;update,versions=v1alpha1,name=vopensearchindexpolicy.kb.io,admissionReviewVersions=v1
+type OpenSearchIndexPolicyValidator struct{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OpenSearchIndexPolicyValidator) ValidateCreate(ctx context.Context, policy *v1alpha1.OpenSearchIndexPolicy) (warnings admission.Warnings, err error) {
+	opensearchindexpolicylog.Info("validate create", "name", policy.Name)
+
+	if err := validateIndexPolicySpec(&policy.Spec); err != nil {
+		return warnings, err
+	}
+
+	clusterPath := field.NewPath("spec").Child("cluster")
+	cluster, err := lookupReferencedCluster(ctx, policy.Namespace, policy.Spec.Cluster, clusterPath)
+	if err != nil {
+		return warnings, err
+	}
+	if err := validateClusterProviderConfig(policy.Spec.ProviderConfigRef, cluster.providerConfig); err != nil {
+		return warnings, err
+	}
+
+	switch cluster.engine {
+	case "":
+		// This is most likely for DatabaseClusterReferences that haven't been
+		// reconciled yet.
+		return warnings, errors.New("could not determine database engine")
+	case "opensearch":
+	default:
+		return warnings, field.Invalid(clusterPath, policy.Spec.Cluster, "index policies are only supported for OpenSearch databases")
+	}
+
+	if !policy.Spec.DryRun {
+		warnings = append(warnings, "indexes outside of the policy will be deleted; set dryRun to preview deletions first")
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OpenSearchIndexPolicyValidator) ValidateUpdate(ctx context.Context, oldPolicy, newPolicy *v1alpha1.OpenSearchIndexPolicy) (warnings admission.Warnings, err error) {
+	opensearchindexpolicylog.Info("validate update", "name", newPolicy.Name)
+
+	clusterPath := field.NewPath("spec").Child("cluster")
+	if !cmp.Equal(newPolicy.Spec.Cluster, oldPolicy.Spec.Cluster) {
+		return warnings, field.Forbidden(clusterPath, "cluster is immutable")
+	}
+	if err := validateProviderConfigRefUnchanged(oldPolicy.Spec.ProviderConfigRef, newPolicy.Spec.ProviderConfigRef); err != nil {
+		return warnings, err
+	}
+
+	return warnings, validateIndexPolicySpec(&newPolicy.Spec)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *OpenSearchIndexPolicyValidator) ValidateDelete(ctx context.Context, policy *v1alpha1.OpenSearchIndexPolicy) (warnings admission.Warnings, err error) {
+	opensearchindexpolicylog.Info("validate delete", "name", policy.Name)
+	return warnings, nil
+}
+
+// validateIndexPolicySpec checks that the evaluation interval is sane and that
+// every rule has a valid pattern and at least one limit.
+func validateIndexPolicySpec(spec *v1alpha1.OpenSearchIndexPolicySpec) error {
+	var (
+		rules     = spec.Rules
+		rulesPath = field.NewPath("spec").Child("rules")
+	)
+
+	if spec.Interval != nil && spec.Interval.Duration < time.Minute {
+		return field.Invalid(field.NewPath("spec").Child("interval"), spec.Interval.Duration.String(), "interval must be at least one minute")
+	}
+	if len(rules) == 0 {
+		return field.Required(rulesPath, "at least one rule must be set")
+	}
+	for i, rule := range rules {
+		rulePath := rulesPath.Index(i)
+		if rule.Pattern == "" {
+			return field.Required(rulePath.Child("pattern"), "pattern must be set")
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return field.Invalid(rulePath.Child("pattern"), rule.Pattern, err.Error())
+		}
+		if rule.MaxAge == nil && rule.MaxTotalSize == nil {
+			return field.Required(rulePath, "one of maxAge or maxTotalSize must be set")
+		}
+		if rule.MaxAge != nil && rule.MaxAge.Duration <= 0 {
+			return field.Invalid(rulePath.Child("maxAge"), rule.MaxAge.Duration.String(), "maxAge must be positive")
+		}
+		if rule.MaxTotalSize != nil && rule.MaxTotalSize.Sign() <= 0 {
+			return field.Invalid(rulePath.Child("maxTotalSize"), rule.MaxTotalSize.String(), "maxTotalSize must be positive")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var existingOpenSearchDB *v1alpha1.DatabaseCluster
+
+func createOpenSearchIndexPolicyWebhookTestFixtures() {
+	existingOpenSearchDB = createDatabaseClusterFixture("opensearch")
+}
+
+func newOpenSearchIndexPolicy(name string, cluster *v1alpha1.DatabaseCluster) *v1alpha1.OpenSearchIndexPolicy {
+	return &v1alpha1.OpenSearchIndexPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       v1alpha1.OpenSearchIndexPolicyKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.OpenSearchIndexPolicySpec{
+			Cluster: corev1.TypedLocalObjectReference{
+				APIGroup: &v1alpha1.GroupVersion.Group,
+				Kind:     v1alpha1.DatabaseClusterKind,
+				Name:     cluster.Name,
+			},
+			Rules: []v1alpha1.IndexRetentionRule{{
+				Pattern: "logs-*",
+				MaxAge:  &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			}},
+			DryRun: true,
+		},
+	}
+}
+
+var _ = Describe("OpenSearchIndexPolicy validating webhook", func() {
+	Context("When creating an OpenSearchIndexPolicy", func() {
+		It("should reject if the cluster is not an OpenSearch cluster", func() {
+			policy := newOpenSearchIndexPolicy("not-opensearch", existingDB)
+
+			err := k8sClient.Create(ctx, policy)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a rule without limits", func() {
+			policy := newOpenSearchIndexPolicy("no-limits", existingOpenSearchDB)
+			policy.Spec.Rules[0].MaxAge = nil
+
+			err := k8sClient.Create(ctx, policy)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject a malformed pattern", func() {
+			policy := newOpenSearchIndexPolicy("bad-pattern", existingOpenSearchDB)
+			policy.Spec.Rules[0].Pattern = "logs-["
+
+			err := k8sClient.Create(ctx, policy)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should accept a valid policy", func() {
+			policy := newOpenSearchIndexPolicy("valid-policy", existingOpenSearchDB)
+
+			err := k8sClient.Create(ctx, policy)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When updating an OpenSearchIndexPolicy", func() {
+		It("should allow changes to the rules", func() {
+			policy := newOpenSearchIndexPolicy("policy-to-update", existingOpenSearchDB)
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+
+			updatedPolicy := policy.DeepCopy()
+			updatedPolicy.Spec.Rules[0].MaxAge = &metav1.Duration{Duration: 14 * 24 * time.Hour}
+			err := k8sClient.Patch(ctx, updatedPolicy, client.MergeFrom(policy))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -1,18 +1,34 @@
 package webhooks
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
-	// godoClient is the godo client used to validate objects in validating
-	// webhooks. It's a package global because there's no tidy way to scope it to a
-	// specific webhook, and it's concurrency-safe anyway.
-	godoClient     *godo.Client
-	godoClientOnce sync.Once
+	// godoClients resolves the godo client used to validate objects in
+	// validating webhooks. It's a package global because there's no tidy way to
+	// scope it to a specific webhook, and it's concurrency-safe anyway.
+	godoClients     *apitoken.ClientCache
+	godoClientsOnce sync.Once
 
 	// webhookClient is the k8s client used to validate objects in validating
 	// webhooks. It's a package global because there's no tidy way to scope it
@@ -21,14 +37,57 @@ var (
 	webhookClientOnce sync.Once
 )
 
-// initGlobalGodoClient initializes the package-global godo client. It should be
-// called by each webhook setup function.
-func initGlobalGodoClient(client *godo.Client) {
-	godoClientOnce.Do(func() {
-		godoClient = client
+// initGlobalGodoClients initializes the package-global godo client cache. It
+// should be called by each webhook setup function.
+func initGlobalGodoClients(clients *apitoken.ClientCache) {
+	godoClientsOnce.Do(func() {
+		godoClients = clients
 	})
 }
 
+// godoClientFor returns the godo client for an object in namespace that names
+// the provider config ref. A reference to a missing provider config is
+// reported as a field error.
+func godoClientFor(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (*godo.Client, error) {
+	godoClient, err := godoClients.ClientFor(ctx, namespace, ref)
+	if errors.Is(err, apitoken.ErrProviderConfigNotFound) {
+		return nil, field.NotFound(field.NewPath("spec").Child("providerConfigRef").Child("name"), ref.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting DigitalOcean client: %v", err)
+	}
+	return godoClient, nil
+}
+
+// validateProviderConfigRefUnchanged checks that an object's provider config
+// isn't changed, since the object's DigitalOcean resources belong to the team
+// of the original provider config.
+func validateProviderConfigRefUnchanged(oldRef, newRef *corev1.LocalObjectReference) error {
+	if providerConfigName(oldRef) != providerConfigName(newRef) {
+		return field.Forbidden(field.NewPath("spec").Child("providerConfigRef"), "providerConfigRef is immutable")
+	}
+	return nil
+}
+
+// validateClusterProviderConfig checks that an object uses the same provider
+// config as the database cluster it references, since a team's API token can
+// only manage the team's own clusters.
+func validateClusterProviderConfig(ref *corev1.LocalObjectReference, clusterProviderConfig string) error {
+	if name := providerConfigName(ref); name != clusterProviderConfig {
+		return field.Invalid(field.NewPath("spec").Child("providerConfigRef"), name,
+			fmt.Sprintf("must match the referenced cluster's provider config %q", clusterProviderConfig))
+	}
+	return nil
+}
+
+// providerConfigName returns the name of the provider config an object uses.
+func providerConfigName(ref *corev1.LocalObjectReference) string {
+	if ref == nil || ref.Name == "" {
+		return v1alpha1.DefaultProviderConfigName
+	}
+	return ref.Name
+}
+
 // initGlobalK8sClient initializes the package-global k8s client. It should be
 // called by the webhook setup functions for all webhooks that require a k8s
 // client.
@@ -51,3 +110,180 @@ func engineOptsFromOptions(opts *godo.DatabaseOptions, engine string) (godo.Data
 	}
 	return godo.DatabaseEngineOptions{}, false
 }
+
+// referencedCluster holds the details of a DatabaseCluster or
+// DatabaseClusterReference needed to validate objects that reference it.
+type referencedCluster struct {
+	uuid   string
+	engine string
+	// providerConfig is the name of the provider config the cluster uses.
+	providerConfig string
+}
+
+// lookupReferencedCluster validates a reference to a DatabaseCluster or
+// DatabaseClusterReference in the given namespace and fetches the referenced
+// object. Problems with the reference itself are returned as field errors on
+// clusterPath.
+func lookupReferencedCluster(ctx context.Context, namespace string, ref corev1.TypedLocalObjectReference, clusterPath *field.Path) (*referencedCluster, error) {
+	clusterAPIGroup := pointer.StringDeref(ref.APIGroup, "")
+	if clusterAPIGroup != v1alpha1.GroupVersion.Group {
+		return nil, field.Invalid(clusterPath.Child("apiGroup"), clusterAPIGroup, "apiGroup must be "+v1alpha1.GroupVersion.Group)
+	}
+
+	clusterNN := types.NamespacedName{
+		Namespace: namespace,
+		Name:      ref.Name,
+	}
+
+	switch strings.ToLower(ref.Kind) {
+	case strings.ToLower(v1alpha1.DatabaseClusterKind):
+		var cluster v1alpha1.DatabaseCluster
+		if err := webhookClient.Get(ctx, clusterNN, &cluster); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, field.NotFound(clusterPath, clusterNN)
+			}
+			return nil, fmt.Errorf("failed to fetch DatabaseCluster %s: %s", clusterNN, err)
+		}
+		return &referencedCluster{
+			uuid:           cluster.Status.UUID,
+			engine:         cluster.Spec.Engine,
+			providerConfig: providerConfigName(cluster.Spec.ProviderConfigRef),
+		}, nil
+	case strings.ToLower(v1alpha1.DatabaseClusterReferenceKind):
+		var clusterRef v1alpha1.DatabaseClusterReference
+		if err := webhookClient.Get(ctx, clusterNN, &clusterRef); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, field.NotFound(clusterPath, clusterNN)
+			}
+			return nil, fmt.Errorf("failed to fetch DatabaseClusterReference %s: %s", clusterNN, err)
+		}
+		return &referencedCluster{
+			uuid:           clusterRef.Spec.UUID,
+			engine:         clusterRef.Status.Engine,
+			providerConfig: providerConfigName(clusterRef.Spec.ProviderConfigRef),
+		}, nil
+	}
+
+	return nil, field.TypeInvalid(
+		clusterPath.Child("kind"),
+		ref.Kind,
+		"kind must be DatabaseCluster or DatabaseClusterReference",
+	)
+}
+
+// lookupGrantedCluster is like lookupReferencedCluster for references that may
+// point to another namespace. References to another namespace must be allowed
+// by a DatabaseClusterGrant in that namespace.
+func lookupGrantedCluster(ctx context.Context, namespace string, ref v1alpha1.ClusterReference, clusterPath *field.Path) (*referencedCluster, error) {
+	clusterNamespace := ref.NamespaceFor(namespace)
+	if clusterNamespace != namespace {
+		namespacePath := clusterPath.Child("namespace")
+		if !isWatchedNamespace(clusterNamespace) {
+			return nil, field.Invalid(namespacePath, clusterNamespace, "namespace is not watched by the operator")
+		}
+		var grants v1alpha1.DatabaseClusterGrantList
+		if err := webhookClient.List(ctx, &grants, client.InNamespace(clusterNamespace)); err != nil {
+			return nil, fmt.Errorf("failed to list DatabaseClusterGrants in %s: %s", clusterNamespace, err)
+		}
+		if !grants.Allows(namespace, ref.Kind, ref.Name) {
+			return nil, field.Forbidden(namespacePath, fmt.Sprintf(
+				"no DatabaseClusterGrant in namespace %s allows references to %s %s from namespace %s",
+				clusterNamespace, ref.Kind, ref.Name, namespace,
+			))
+		}
+	}
+
+	localRef := corev1.TypedLocalObjectReference{
+		APIGroup: ref.APIGroup,
+		Kind:     ref.Kind,
+		Name:     ref.Name,
+	}
+	return lookupReferencedCluster(ctx, clusterNamespace, localRef, clusterPath)
+}
+
+// exampleSecretTemplateData is used to check that secret templates only
+// reference connection fields that exist.
+var exampleSecretTemplateData = &v1alpha1.SecretTemplateData{
+	User:     "example_user",
+	Password: "example_password",
+	Host:     "example.db.ondigitalocean.com",
+	Port:     25060,
+	Database: "defaultdb",
+	SSLMode:  "require",
+	CA:       "-----BEGIN CERTIFICATE-----",
+}
+
+// serviceBindingKeys are the credentials Secret keys that identify it as a
+// Service Binding, which a secretTemplate may not replace.
+var serviceBindingKeys = []string{"type", "provider"}
+
+// validateSecretTemplate checks that the keys of a secretTemplate are valid
+// Secret keys and that its templates parse and render.
+func validateSecretTemplate(secretTemplate map[string]string) error {
+	templatePath := field.NewPath("spec").Child("secretTemplate")
+	for _, key := range slices.Sorted(maps.Keys(secretTemplate)) {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return field.Invalid(templatePath, key, strings.Join(errs, "; "))
+		}
+		if slices.Contains(serviceBindingKeys, key) {
+			return field.Forbidden(templatePath.Key(key), "the Service Binding keys are set by the operator")
+		}
+		if _, err := v1alpha1.ExecuteSecretTemplate(key, secretTemplate[key], exampleSecretTemplateData); err != nil {
+			return field.Invalid(templatePath.Key(key), secretTemplate[key], err.Error())
+		}
+	}
+	return nil
+}
+
+// validateClusterOutput checks the names and metadata configured for the
+// objects generated for a database cluster.
+func validateClusterOutput(output *v1alpha1.ClusterOutput) error {
+	if output == nil {
+		return nil
+	}
+	outputPath := field.NewPath("spec").Child("output")
+
+	errs := validateOutputName(output.ConnectionConfigMapName, outputPath.Child("connectionConfigMapName"))
+	errs = append(errs, validateOutputName(output.PrivateConnectionConfigMapName, outputPath.Child("privateConnectionConfigMapName"))...)
+	errs = append(errs, validateOutputName(output.CredentialsSecretName, outputPath.Child("credentialsSecretName"))...)
+	if output.ConnectionConfigMapName != "" && output.ConnectionConfigMapName == output.PrivateConnectionConfigMapName {
+		errs = append(errs, field.Duplicate(outputPath.Child("privateConnectionConfigMapName"), output.PrivateConnectionConfigMapName))
+	}
+	errs = append(errs, validateOutputMetadata(output.OutputMetadata, outputPath)...)
+
+	return errs.ToAggregate()
+}
+
+// validateUserOutput checks the name and metadata configured for the
+// credentials Secret generated for a database user.
+func validateUserOutput(output *v1alpha1.UserOutput) error {
+	if output == nil {
+		return nil
+	}
+	outputPath := field.NewPath("spec").Child("output")
+
+	errs := validateOutputName(output.CredentialsSecretName, outputPath.Child("credentialsSecretName"))
+	errs = append(errs, validateOutputMetadata(output.OutputMetadata, outputPath)...)
+
+	return errs.ToAggregate()
+}
+
+func validateOutputName(name string, namePath *field.Path) field.ErrorList {
+	if name == "" {
+		return nil
+	}
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(name) {
+		errs = append(errs, field.Invalid(namePath, name, msg))
+	}
+	return errs
+}
+
+func validateOutputMetadata(md v1alpha1.OutputMetadata, outputPath *field.Path) field.ErrorList {
+	errs := metav1validation.ValidateLabels(md.Labels, outputPath.Child("labels"))
+	if value, ok := md.Labels[v1alpha1.ManagedByLabel]; ok && value != v1alpha1.ManagedByValue {
+		errs = append(errs, field.Forbidden(outputPath.Child("labels").Key(v1alpha1.ManagedByLabel), "the managed-by label is set by the operator"))
+	}
+	errs = append(errs, apivalidation.ValidateAnnotations(md.Annotations, outputPath.Child("annotations"))...)
+	return errs
+}
@@ -32,6 +32,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/api/v1beta1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/do-operator/fakegodo"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
@@ -70,7 +72,7 @@ var (
 			},
 			MySQLOptions: godo.DatabaseEngineOptions{
 				Regions:  []string{"dev0"},
-				Versions: []string{"6"},
+				Versions: []string{"6", "10", "8"},
 				Layouts: []godo.DatabaseLayout{
 					{
 						NodeNum: 1,
@@ -113,8 +115,23 @@ var _ = BeforeSuite(func() {
 
 	ctx, cancel = context.WithCancel(context.TODO())
 
+	scheme := runtime.NewScheme()
+	err := v1alpha1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = v1beta1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = admissionv1beta1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	//+kubebuilder:scaffold:scheme
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
+		// The scheme tells envtest which CRDs to install with the conversion
+		// webhook.
+		Scheme:                scheme,
 		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: false,
 		WebhookInstallOptions: envtest.WebhookInstallOptions{
@@ -122,21 +139,11 @@ var _ = BeforeSuite(func() {
 		},
 	}
 
-	var err error
 	// cfg is defined in this file globally.
 	cfg, err = testEnv.Start()
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cfg).NotTo(BeNil())
 
-	scheme := runtime.NewScheme()
-	err = v1alpha1.AddToScheme(scheme)
-	Expect(err).NotTo(HaveOccurred())
-
-	err = admissionv1beta1.AddToScheme(scheme)
-	Expect(err).NotTo(HaveOccurred())
-
-	//+kubebuilder:scaffold:scheme
-
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
@@ -163,17 +170,32 @@ var _ = BeforeSuite(func() {
 	godoClient, err := godo.New(http.DefaultClient, godo.SetBaseURL(godoServer.URL))
 	Expect(err).NotTo(HaveOccurred())
 	godoClient.Databases = fakeDatabasesService
+	godoClients := apitoken.NewClientCache(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetAPIReader(), godoClient, func(*apitoken.Source) (*godo.Client, error) {
+		return godoClient, nil
+	})
+
+	err = SetupDatabaseClusterReferenceWebhookWithManager(mgr, godoClients)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupDatabaseUserWebhookWithManager(mgr, godoClients)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupDatabaseUserReferenceWebhookWithManager(mgr, godoClients)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = SetupDatabaseClusterWebhookWithManager(mgr, godoClients, "dev0", v1alpha1.DriftPolicyObserve)
+	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupDatabaseClusterReferenceWebhookWithManager(mgr, godoClient)
+	err = SetupKafkaSchemaSubjectWebhookWithManager(mgr, godoClients)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupDatabaseUserWebhookWithManager(mgr, godoClient)
+	err = SetupDatabaseLogsinkWebhookWithManager(mgr, godoClients)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupDatabaseUserReferenceWebhookWithManager(mgr, godoClient)
+	err = SetupDatabaseOnlineMigrationWebhookWithManager(mgr, godoClients)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupDatabaseClusterWebhookWithManager(mgr, godoClient)
+	err = SetupOpenSearchIndexPolicyWebhookWithManager(mgr, godoClients)
 	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook
@@ -198,6 +220,8 @@ var _ = BeforeSuite(func() {
 
 	// Create fixtures for tests.
 	createUserWebhookTestFixtures()
+	createKafkaSchemaSubjectWebhookTestFixtures()
+	createOpenSearchIndexPolicyWebhookTestFixtures()
 }, 60)
 
 var _ = AfterSuite(func() {
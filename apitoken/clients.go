@@ -0,0 +1,201 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var (
+	// ErrProviderConfigNotFound is returned when an object names a
+	// DigitalOceanProviderConfig that doesn't exist.
+	ErrProviderConfigNotFound = errors.New("DigitalOceanProviderConfig not found")
+	// ErrCredentialsNotFound is returned when a DigitalOceanProviderConfig's
+	// credentials Secret doesn't exist or doesn't hold a token.
+	ErrCredentialsNotFound = errors.New("DigitalOceanProviderConfig credentials not found")
+)
+
+// NewClientFunc builds a godo client that authenticates with the token from
+// the given Source.
+type NewClientFunc func(*Source) (*godo.Client, error)
+
+// ClientCache resolves the godo client to use for an object from the
+// DigitalOceanProviderConfig it names. Clients are cached per provider config
+// and pick up changes to the config's token Secret without being rebuilt. A
+// client is dropped once its provider config or token Secret is found to be
+// gone, and rebuilt if the provider config is re-created.
+type ClientCache struct {
+	reader         client.Reader
+	secretMetadata client.Reader
+	secretReader   client.Reader
+	defaultClient  *godo.Client
+	newClient      NewClientFunc
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]*cachedClient
+}
+
+type cachedClient struct {
+	// configUID is the UID of the provider config the client was built for.
+	configUID types.UID
+	// secret and key locate the token, and secretVersion is the
+	// resourceVersion of the Secret it was read from.
+	secret        types.NamespacedName
+	key           string
+	secretVersion string
+	source        *Source
+	client        *godo.Client
+}
+
+// NewClientCache returns a ClientCache that reads provider configs with reader
+// and their credentials Secrets with secretReader, which must see Secrets that
+// weren't generated by the operator. A Secret is only read again once its
+// resourceVersion, which is read from secretMetadata, changes, so
+// secretMetadata should be a cache of Secret metadata. defaultClient is used
+// for objects that don't name a provider config when their namespace has no
+// default provider config.
+func NewClientCache(reader, secretMetadata, secretReader client.Reader, defaultClient *godo.Client, newClient NewClientFunc) *ClientCache {
+	return &ClientCache{
+		reader:         reader,
+		secretMetadata: secretMetadata,
+		secretReader:   secretReader,
+		defaultClient:  defaultClient,
+		newClient:      newClient,
+		clients:        make(map[types.NamespacedName]*cachedClient),
+	}
+}
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=digitaloceanproviderconfigs,verbs=get;list;watch
+
+// ClientFor returns the godo client for an object in namespace that names the
+// provider config ref, which may be nil. Objects that don't name a provider
+// config use the namespace's default provider config if there is one, and
+// otherwise the default client.
+func (c *ClientCache) ClientFor(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (*godo.Client, error) {
+	name := v1alpha1.DefaultProviderConfigName
+	if ref != nil && ref.Name != "" {
+		name = ref.Name
+	}
+	configNN := types.NamespacedName{Namespace: namespace, Name: name}
+
+	var config v1alpha1.DigitalOceanProviderConfig
+	if err := c.reader.Get(ctx, configNN, &config); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("getting DigitalOceanProviderConfig %s: %w", configNN, err)
+		}
+		c.forget(configNN)
+		if ref != nil && ref.Name != "" {
+			return nil, fmt.Errorf("%w: %s", ErrProviderConfigNotFound, configNN)
+		}
+		return c.defaultClient, nil
+	}
+
+	secretRef := config.Spec.CredentialsSecretRef
+	key := secretRef.Key
+	if key == "" {
+		key = v1alpha1.DefaultProviderConfigTokenKey
+	}
+	secretNN := types.NamespacedName{Namespace: config.Namespace, Name: secretRef.Name}
+
+	version, err := c.secretVersion(ctx, &config, secretNN)
+	if err != nil {
+		if errors.Is(err, ErrCredentialsNotFound) {
+			c.forget(configNN)
+		}
+		return nil, err
+	}
+	if godoClient := c.cached(configNN, config.UID, secretNN, key, version); godoClient != nil {
+		return godoClient, nil
+	}
+
+	token, version, err := c.tokenFor(ctx, &config, secretNN, key)
+	if err != nil {
+		if errors.Is(err, ErrCredentialsNotFound) {
+			c.forget(configNN)
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[configNN]; ok && cached.configUID == config.UID {
+		cached.source.Set(token)
+		cached.secret, cached.key, cached.secretVersion = secretNN, key, version
+		return cached.client, nil
+	}
+	source := NewSource(token)
+	godoClient, err := c.newClient(source)
+	if err != nil {
+		return nil, fmt.Errorf("creating godo client for DigitalOceanProviderConfig %s: %w", configNN, err)
+	}
+	c.clients[configNN] = &cachedClient{
+		configUID:     config.UID,
+		secret:        secretNN,
+		key:           key,
+		secretVersion: version,
+		source:        source,
+		client:        godoClient,
+	}
+	return godoClient, nil
+}
+
+// cached returns the cached client for a provider config if its token was
+// read from the given version of the Secret, and otherwise nil.
+func (c *ClientCache) cached(configNN types.NamespacedName, configUID types.UID, secretNN types.NamespacedName, key, secretVersion string) *godo.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.clients[configNN]
+	if !ok || cached.configUID != configUID || cached.secret != secretNN || cached.key != key || cached.secretVersion != secretVersion {
+		return nil
+	}
+	return cached.client
+}
+
+// forget drops the cached client for a provider config.
+func (c *ClientCache) forget(configNN types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.clients, configNN)
+}
+
+// secretVersion returns the resourceVersion of a provider config's
+// credentials Secret.
+func (c *ClientCache) secretVersion(ctx context.Context, config *v1alpha1.DigitalOceanProviderConfig, secretNN types.NamespacedName) (string, error) {
+	var secret metav1.PartialObjectMetadata
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := c.secretMetadata.Get(ctx, secretNN, &secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", fmt.Errorf("%w: Secret %s for DigitalOceanProviderConfig %s doesn't exist", ErrCredentialsNotFound, secretNN, config.Name)
+		}
+		return "", fmt.Errorf("getting credentials Secret %s for DigitalOceanProviderConfig %s: %w", secretNN, config.Name, err)
+	}
+	return secret.ResourceVersion, nil
+}
+
+// tokenFor reads the API token from a provider config's credentials Secret,
+// and returns it along with the Secret's resourceVersion.
+func (c *ClientCache) tokenFor(ctx context.Context, config *v1alpha1.DigitalOceanProviderConfig, secretNN types.NamespacedName, key string) (string, string, error) {
+	var secret corev1.Secret
+	if err := c.secretReader.Get(ctx, secretNN, &secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", "", fmt.Errorf("%w: Secret %s for DigitalOceanProviderConfig %s doesn't exist", ErrCredentialsNotFound, secretNN, config.Name)
+		}
+		return "", "", fmt.Errorf("getting credentials Secret %s for DigitalOceanProviderConfig %s: %w", secretNN, config.Name, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		return "", "", fmt.Errorf("%w: Secret %s for DigitalOceanProviderConfig %s has no %q key", ErrCredentialsNotFound, secretNN, config.Name, key)
+	}
+	return token, secret.ResourceVersion, nil
+}
@@ -0,0 +1,166 @@
+// Package apitoken provides a DigitalOcean API token that can be replaced
+// while the operator is running, so that tokens can be rotated without a
+// restart.
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	// ErrNoToken is returned when no API token has been loaded yet.
+	ErrNoToken = errors.New("no DigitalOcean API token is configured")
+	// ErrTokenRejected is returned when the DigitalOcean API has rejected the
+	// current API token.
+	ErrTokenRejected = errors.New("the DigitalOcean API token was rejected")
+)
+
+// Source is an oauth2.TokenSource for a DigitalOcean API token that can be
+// replaced at any time. It also tracks whether the API has rejected the
+// current token, for use in readiness checks.
+type Source struct {
+	mu       sync.RWMutex
+	token    string
+	rejected bool
+}
+
+// NewSource returns a Source with the given initial token, which may be empty.
+func NewSource(token string) *Source {
+	return &Source{token: token}
+}
+
+// Token implements oauth2.TokenSource.
+func (s *Source) Token() (*oauth2.Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.token == "" {
+		return nil, ErrNoToken
+	}
+	return &oauth2.Token{AccessToken: s.token}, nil
+}
+
+// Set replaces the token. It returns whether the token changed.
+func (s *Source) Set(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token == s.token {
+		return false
+	}
+	s.token = token
+	s.rejected = false
+	return true
+}
+
+// Check returns an error if there is no token or the API rejected the current
+// token. Its signature matches healthz.Checker.
+func (s *Source) Check(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch {
+	case s.token == "":
+		return ErrNoToken
+	case s.rejected:
+		return ErrTokenRejected
+	}
+	return nil
+}
+
+// HTTPClient returns an HTTP client that authenticates requests with the
+// current token and records whether the API rejects it.
+func (s *Source) HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &rejectionTracker{
+			source: s,
+			// Don't wrap the source in an oauth2.ReuseTokenSource, which
+			// would cache the token forever since it doesn't expire.
+			base: &oauth2.Transport{Source: s},
+		},
+	}
+}
+
+// observe records the API's response to a request made with token.
+func (s *Source) observe(token string, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token != s.token {
+		// The token was replaced while the request was in flight.
+		return
+	}
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		s.rejected = true
+	case statusCode < 400:
+		s.rejected = false
+	}
+}
+
+// rejectionTracker is an http.RoundTripper that reports API responses to its
+// Source.
+type rejectionTracker struct {
+	source *Source
+	base   http.RoundTripper
+}
+
+func (t *rejectionTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.source.mu.RLock()
+	token := t.source.token
+	t.source.mu.RUnlock()
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.source.observe(token, resp.StatusCode)
+	}
+	return resp, err
+}
+
+// FileLoader keeps a Source up to date with the token in a file, such as a
+// mounted Secret. It implements manager.Runnable.
+type FileLoader struct {
+	Source *Source
+	Path   string
+	// Interval is how often the file is re-read.
+	Interval time.Duration
+}
+
+// Load reads the token from the file once.
+func (l *FileLoader) Load() error {
+	b, err := os.ReadFile(l.Path)
+	if err != nil {
+		return err
+	}
+	l.Source.Set(strings.TrimSpace(string(b)))
+	return nil
+}
+
+// Start re-reads the token file until ctx is done.
+func (l *FileLoader) Start(ctx context.Context) error {
+	ll := log.FromContext(ctx).WithValues("path", l.Path)
+
+	ticker := time.NewTicker(l.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := l.Load(); err != nil {
+				ll.Error(err, "unable to read API token file")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every replica
+// serves webhooks, so every replica needs the token.
+func (l *FileLoader) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/digitalocean/do-operator/apitoken"
+)
+
+// APITokenSecretReconciler keeps the DigitalOcean API token up to date with
+// the token stored in a Secret.
+type APITokenSecretReconciler struct {
+	// SecretKey identifies the Secret holding the token.
+	SecretKey types.NamespacedName
+	// DataKey is the key of the token in the Secret's data.
+	DataKey string
+	// Source is updated with the token.
+	Source *apitoken.Source
+
+	// secrets is a cache of the token Secret alone, since the manager's cache
+	// only holds the Secrets generated by the operator.
+	secrets cache.Cache
+}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile loads the token from the Secret. If the Secret or the key is
+// missing, the token is cleared so that the operator reports itself unready
+// rather than using a token that may have been revoked.
+func (r *APITokenSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ll := log.FromContext(ctx)
+
+	var secret corev1.Secret
+	err := r.secrets.Get(ctx, req.NamespacedName, &secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("getting API token Secret: %w", err)
+	}
+
+	token := strings.TrimSpace(string(secret.Data[r.DataKey]))
+	if r.Source.Set(token) {
+		if token == "" {
+			ll.Info("API token Secret is missing the token", "key", r.DataKey)
+		} else {
+			ll.Info("loaded API token from Secret")
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *APITokenSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	secrets, err := addCache(mgr, cache.Options{
+		DefaultNamespaces: map[string]cache.Config{r.SecretKey.Namespace: {}},
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", r.SecretKey.Name)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	r.secrets = secrets
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("apitokensecret").
+		WatchesRawSource(source.Kind[client.Object](secrets, &corev1.Secret{}, &handler.EnqueueRequestForObject{})).
+		WithOptions(controller.Options{
+			// Every replica serves webhooks, so every replica needs the token.
+			NeedLeaderElection: pointer.Bool(false),
+		}).
+		Complete(r)
+}
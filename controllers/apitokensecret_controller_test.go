@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/apitoken"
+)
+
+var _ = Describe("APITokenSecret controller", func() {
+	Context("When reconciling the API token Secret", func() {
+		It("should load, reload and clear the token", func() {
+			currentToken := func() (string, error) {
+				token, err := apiTokenSource.Token()
+				if err != nil {
+					return "", err
+				}
+				return token.AccessToken, nil
+			}
+
+			By("ensuring the token is missing before the Secret exists", func() {
+				Expect(apiTokenSource.Check(nil)).To(MatchError(apitoken.ErrNoToken))
+			})
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      apiTokenSecretKey.Name,
+					Namespace: apiTokenSecretKey.Namespace,
+				},
+				StringData: map[string]string{
+					"access-token": "first-token",
+				},
+			}
+
+			By("creating the Secret", func() {
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			})
+
+			By("ensuring the token is loaded", func() {
+				Eventually(currentToken, timeout, interval).Should(Equal("first-token"))
+				Expect(apiTokenSource.Check(nil)).To(Succeed())
+			})
+
+			By("rotating the token in the Secret", func() {
+				updatedSecret := secret.DeepCopy()
+				updatedSecret.StringData = map[string]string{
+					"access-token": "second-token\n",
+				}
+				Expect(k8sClient.Patch(ctx, updatedSecret, client.MergeFrom(secret))).To(Succeed())
+			})
+
+			By("ensuring the new token is loaded", func() {
+				Eventually(currentToken, timeout, interval).Should(Equal("second-token"))
+			})
+
+			By("deleting the Secret", func() {
+				Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			})
+
+			By("ensuring the token is cleared", func() {
+				Eventually(func() error {
+					return apiTokenSource.Check(nil)
+				}, timeout, interval).Should(MatchError(apitoken.ErrNoToken))
+			})
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// GeneratedObjectsOnly returns the manager cache options that limit the cached
+// Secrets and ConfigMaps to those generated by the operator, which carry the
+// managed-by label. Other Secrets and ConfigMaps, such as those holding
+// credentials or schemas, are read directly from the API server, so that the
+// operator doesn't keep every Secret in the cluster in memory.
+func GeneratedObjectsOnly() map[client.Object]cache.ByObject {
+	generated := labels.SelectorFromSet(labels.Set{v1alpha1.ManagedByLabel: v1alpha1.ManagedByValue})
+	return map[client.Object]cache.ByObject{
+		&corev1.Secret{}:    {Label: generated},
+		&corev1.ConfigMap{}: {Label: generated},
+	}
+}
+
+// getGenerated gets a generated object, first from the cache and then, if
+// it isn't cached, from the API server with apiReader. Objects generated
+// before the managed-by label was added aren't cached until they're next
+// applied.
+func getGenerated(ctx context.Context, c, apiReader client.Reader, key client.ObjectKey, obj client.Object) error {
+	err := c.Get(ctx, key, obj)
+	if !kerrors.IsNotFound(err) {
+		return err
+	}
+	return apiReader.Get(ctx, key, obj)
+}
+
+// addCache creates a cache alongside the manager's, for watching objects the
+// manager's cache leaves out, and adds it to the manager.
+func addCache(mgr ctrl.Manager, opts cache.Options) (cache.Cache, error) {
+	opts.Scheme = mgr.GetScheme()
+	opts.Mapper = mgr.GetRESTMapper()
+	c, err := cache.New(mgr.GetConfig(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("creating cache: %w", err)
+	}
+	if err := mgr.Add(unelectedCache{c}); err != nil {
+		return nil, fmt.Errorf("adding cache to manager: %w", err)
+	}
+	return c, nil
+}
+
+// AddMetadataCache adds a cache to the manager for the metadata of objects
+// that the manager's cache leaves out, such as the Secrets and ConfigMaps
+// that the operator doesn't generate. It covers namespaces, or every
+// namespace if there are none. An object's metadata changes along with its
+// contents, so watching the metadata is enough to notice changes without
+// keeping every object in memory.
+func AddMetadataCache(mgr ctrl.Manager, namespaces []string) (cache.Cache, error) {
+	var cacheNamespaces map[string]cache.Config
+	if len(namespaces) > 0 {
+		cacheNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			cacheNamespaces[ns] = cache.Config{}
+		}
+	}
+	return addCache(mgr, cache.Options{DefaultNamespaces: cacheNamespaces})
+}
+
+// unelectedCache runs a cache on every replica rather than only the leader,
+// like the manager's own cache.
+type unelectedCache struct {
+	cache.Cache
+}
+
+func (unelectedCache) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var _ = Describe("Manager cache", func() {
+	It("should only cache Secrets generated by the operator", func() {
+		generated := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "generated-secret",
+				Namespace: "default",
+				Labels:    map[string]string{v1alpha1.ManagedByLabel: v1alpha1.ManagedByValue},
+			},
+		}
+		Expect(k8sClient.Create(ctx, generated)).To(Succeed())
+		userOwned := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-owned-secret",
+				Namespace: "default",
+			},
+		}
+		Expect(k8sClient.Create(ctx, userOwned)).To(Succeed())
+
+		cached := k8sManager.GetClient()
+		Eventually(func() error {
+			return cached.Get(ctx, client.ObjectKeyFromObject(generated), &corev1.Secret{})
+		}, timeout, interval).Should(Succeed())
+		Consistently(func() bool {
+			err := cached.Get(ctx, client.ObjectKeyFromObject(userOwned), &corev1.Secret{})
+			return kerrors.IsNotFound(err)
+		}, "2s", interval).Should(BeTrue())
+
+		Expect(k8sManager.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(userOwned), &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should replace an unlabelled Secret of another type", func() {
+		owner := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unlabelled-secret-owner",
+				Namespace: "default",
+			},
+		}
+		Expect(k8sClient.Create(ctx, owner)).To(Succeed())
+		// Generated before the managed-by label and Service Binding types.
+		existing := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "unlabelled-secret",
+				Namespace: "default",
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		Expect(controllerutil.SetControllerReference(owner, existing, k8sManager.GetScheme())).To(Succeed())
+		Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      existing.Name,
+				Namespace: existing.Namespace,
+			},
+			Type: "servicebinding.io/postgresql",
+		}
+		Expect(deleteSecretOfOtherType(ctx, k8sManager.GetClient(), k8sManager.GetAPIReader(), owner, secret)).To(Succeed())
+
+		err := k8sManager.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(existing), &corev1.Secret{})
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	})
+})
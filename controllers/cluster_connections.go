@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// clusterConnectionsTTL is how long a database cluster fetched for its
+// connection details is reused before it's fetched again.
+const clusterConnectionsTTL = 5 * time.Minute
+
+// clusterConnections caches the database clusters that credentials Secrets
+// are built from, so that reconciling the users of a cluster doesn't fetch the
+// cluster from the DigitalOcean API for every user and every reconcile. The
+// zero value is ready to use.
+//
+// Clusters are cached along with their connection hash, so a cluster is
+// fetched again as soon as its DatabaseCluster or DatabaseClusterReference
+// reports new connection details.
+type clusterConnections struct {
+	mu       sync.Mutex
+	clusters map[string]cachedCluster
+}
+
+type cachedCluster struct {
+	db             *godo.Database
+	connectionHash string
+	fetchedAt      time.Time
+}
+
+// get returns the database cluster with the given UUID, fetching it if it
+// isn't cached, the cached copy was fetched for a different connection hash,
+// or it's older than clusterConnectionsTTL. The returned cluster is shared and
+// must not be modified.
+func (c *clusterConnections) get(ctx context.Context, godoClient *godo.Client, uuid, connectionHash string) (*godo.Database, error) {
+	c.mu.Lock()
+	cached, ok := c.clusters[uuid]
+	c.mu.Unlock()
+	if ok && cached.connectionHash == connectionHash && time.Since(cached.fetchedAt) < clusterConnectionsTTL {
+		return cached.db, nil
+	}
+
+	db, _, err := godoClient.Databases.Get(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("getting DB cluster: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clusters == nil {
+		c.clusters = make(map[string]cachedCluster)
+	}
+	// Drop stale entries, such as those of deleted clusters.
+	for cachedUUID, cached := range c.clusters {
+		if time.Since(cached.fetchedAt) >= clusterConnectionsTTL {
+			delete(c.clusters, cachedUUID)
+		}
+	}
+	c.clusters[uuid] = cachedCluster{db: db, connectionHash: connectionHash, fetchedAt: time.Now()}
+	return db, nil
+}
+
+// connectionHash returns a hash of the connection details of a database
+// cluster that are copied into credentials Secrets, which changes whenever
+// those Secrets need updating.
+func connectionHash(db *godo.Database) string {
+	h := sha256.New()
+	for _, conn := range []*godo.DatabaseConnection{db.Connection, db.PrivateConnection} {
+		if conn == nil {
+			h.Write([]byte("-\n"))
+			continue
+		}
+		h.Write([]byte(conn.Host + ":" + strconv.Itoa(conn.Port) + "/" + conn.Database + " ssl=" + strconv.FormatBool(conn.SSL) + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
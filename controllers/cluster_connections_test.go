@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// countingDatabasesService counts the clusters fetched through it.
+type countingDatabasesService struct {
+	godo.DatabasesService
+	gets atomic.Int32
+}
+
+func (s *countingDatabasesService) Get(ctx context.Context, dbUUID string) (*godo.Database, *godo.Response, error) {
+	s.gets.Add(1)
+	return s.DatabasesService.Get(ctx, dbUUID)
+}
+
+var _ = Describe("Cluster connections", func() {
+	It("should fetch each cluster once for all of its users", func() {
+		dbCluster := mustCreateDatabaseCluster()
+		service := &countingDatabasesService{DatabasesService: fakeDatabasesService}
+		godoClient := godo.NewFromToken("")
+		godoClient.Databases = service
+
+		var connections clusterConnections
+		for i := 0; i < 3; i++ {
+			db, err := connections.get(ctx, godoClient, dbCluster.Status.UUID, "hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(db.ID).To(Equal(dbCluster.Status.UUID))
+		}
+		Expect(service.gets.Load()).To(BeEquivalentTo(1))
+
+		By("fetching the cluster again when its connection details change", func() {
+			_, err := connections.get(ctx, godoClient, dbCluster.Status.UUID, "new-hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.gets.Load()).To(BeEquivalentTo(2))
+		})
+	})
+})
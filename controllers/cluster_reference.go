@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// clusterNotReadyRequeueTime is how long objects wait for a cluster that isn't
+// ready before they're reconciled again. They're normally reconciled as soon as
+// the cluster's status changes, so this is only a fallback.
+const clusterNotReadyRequeueTime = 2 * time.Minute
+
+// referencedCluster holds the details of a DatabaseCluster or
+// DatabaseClusterReference that objects referencing it need to reconcile.
+type referencedCluster struct {
+	// UUID is the UUID of the database cluster. It is empty if a
+	// DatabaseCluster has not been created yet.
+	UUID string
+	// Engine is the database engine slug.
+	Engine string
+	// Status is the last observed status of the database cluster.
+	Status string
+	// ConnectionHash is a hash of the database cluster's connection details.
+	ConnectionHash string
+}
+
+// getReferencedCluster fetches the DatabaseCluster or DatabaseClusterReference
+// that ref points to in the given namespace.
+func getReferencedCluster(ctx context.Context, c client.Client, namespace string, ref corev1.TypedLocalObjectReference) (*referencedCluster, error) {
+	clusterNN := types.NamespacedName{
+		Namespace: namespace,
+		Name:      ref.Name,
+	}
+
+	switch ref.Kind {
+	case v1alpha1.DatabaseClusterKind:
+		var cluster v1alpha1.DatabaseCluster
+		if err := c.Get(ctx, clusterNN, &cluster); err != nil {
+			return nil, fmt.Errorf("failed to get DatabaseCluster %s: %s", clusterNN.Name, err)
+		}
+		return clusterFromObject(&cluster), nil
+	case v1alpha1.DatabaseClusterReferenceKind:
+		var clusterRef v1alpha1.DatabaseClusterReference
+		if err := c.Get(ctx, clusterNN, &clusterRef); err != nil {
+			return nil, fmt.Errorf("failed to get DatabaseClusterReference %s: %s", clusterNN.Name, err)
+		}
+		return clusterFromObject(&clusterRef), nil
+	}
+
+	// Validating webhooks should ensure we never get here.
+	return nil, fmt.Errorf("unexpected Kind for Cluster: %s", ref.Kind)
+}
+
+// getClusterReference fetches the DatabaseCluster or DatabaseClusterReference
+// that ref points to when it's referenced from an object in namespace.
+func getClusterReference(ctx context.Context, c client.Client, namespace string, ref v1alpha1.ClusterReference) (*referencedCluster, error) {
+	return getReferencedCluster(ctx, c, ref.NamespaceFor(namespace), corev1.TypedLocalObjectReference{
+		APIGroup: ref.APIGroup,
+		Kind:     ref.Kind,
+		Name:     ref.Name,
+	})
+}
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclustergrants,verbs=get;list;watch
+
+// checkClusterGrant returns an error unless an object in namespace may
+// reference the cluster ref points to. References within a namespace are
+// always allowed; references to another namespace must be allowed by a
+// DatabaseClusterGrant in that namespace.
+func checkClusterGrant(ctx context.Context, c client.Reader, namespace string, ref v1alpha1.ClusterReference) error {
+	clusterNamespace := ref.NamespaceFor(namespace)
+	if clusterNamespace == namespace {
+		return nil
+	}
+
+	var grants v1alpha1.DatabaseClusterGrantList
+	if err := c.List(ctx, &grants, client.InNamespace(clusterNamespace)); err != nil {
+		return fmt.Errorf("failed to list DatabaseClusterGrants in %s: %s", clusterNamespace, err)
+	}
+	if !grants.Allows(namespace, ref.Kind, ref.Name) {
+		return fmt.Errorf("no DatabaseClusterGrant in namespace %s allows references to %s %s", clusterNamespace, ref.Kind, ref.Name)
+	}
+	return nil
+}
+
+// clusterFromObject returns the details of a DatabaseCluster or
+// DatabaseClusterReference, or nil for any other object.
+func clusterFromObject(obj client.Object) *referencedCluster {
+	switch obj := obj.(type) {
+	case *v1alpha1.DatabaseCluster:
+		return &referencedCluster{
+			UUID:           obj.Status.UUID,
+			Engine:         obj.Spec.Engine,
+			Status:         obj.Status.Status,
+			ConnectionHash: obj.Status.ConnectionHash,
+		}
+	case *v1alpha1.DatabaseClusterReference:
+		return &referencedCluster{
+			UUID:           obj.Spec.UUID,
+			Engine:         obj.Status.Engine,
+			Status:         obj.Status.Status,
+			ConnectionHash: obj.Status.ConnectionHash,
+		}
+	}
+	return nil
+}
+
+// isReady returns true if the cluster exists and is no longer being created,
+// i.e., dependent objects can be created in it.
+func (c *referencedCluster) isReady() bool {
+	return c.UUID != "" && c.Status != "" && c.Status != "creating"
+}
+
+// clusterNameField is the field index of objects that reference a
+// DatabaseCluster or DatabaseClusterReference by the referenced name.
+const clusterNameField = "spec.databaseCluster.name"
+
+// clusterReferenceOf returns the namespace, kind and name of the cluster obj
+// references. ok is false if obj doesn't reference a cluster.
+func clusterReferenceOf(obj client.Object) (namespace, kind, name string, ok bool) {
+	var ref corev1.TypedLocalObjectReference
+	switch obj := obj.(type) {
+	case *v1alpha1.DatabaseUser:
+		return obj.Spec.Cluster.NamespaceFor(obj.Namespace), obj.Spec.Cluster.Kind, obj.Spec.Cluster.Name, true
+	case *v1alpha1.DatabaseUserReference:
+		return obj.Spec.Cluster.NamespaceFor(obj.Namespace), obj.Spec.Cluster.Kind, obj.Spec.Cluster.Name, true
+	case *v1alpha1.DatabaseLogsink:
+		ref = obj.Spec.Cluster
+	case *v1alpha1.DatabaseOnlineMigration:
+		ref = obj.Spec.Cluster
+	case *v1alpha1.KafkaSchemaSubject:
+		ref = obj.Spec.Cluster
+	case *v1alpha1.OpenSearchIndexPolicy:
+		ref = obj.Spec.Cluster
+	default:
+		return "", "", "", false
+	}
+	return obj.GetNamespace(), ref.Kind, ref.Name, true
+}
+
+// indexReferencedCluster adds the clusterNameField index for obj's kind.
+func indexReferencedCluster(mgr ctrl.Manager, obj client.Object) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), obj, clusterNameField, func(obj client.Object) []string {
+		if _, _, name, ok := clusterReferenceOf(obj); ok {
+			return []string{name}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("indexing %s: %w", clusterNameField, err)
+	}
+	return nil
+}
+
+// clusterDependents returns a function that maps a DatabaseCluster or
+// DatabaseClusterReference to the objects of list's kind that reference it.
+// The kind must be indexed with indexReferencedCluster.
+func clusterDependents(c client.Reader, list client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, cluster client.Object) []reconcile.Request {
+		var kind string
+		switch cluster.(type) {
+		case *v1alpha1.DatabaseCluster:
+			kind = v1alpha1.DatabaseClusterKind
+		case *v1alpha1.DatabaseClusterReference:
+			kind = v1alpha1.DatabaseClusterReferenceKind
+		default:
+			return nil
+		}
+
+		dependents := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, dependents, client.MatchingFields{clusterNameField: cluster.GetName()}); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list objects referencing cluster", "cluster", client.ObjectKeyFromObject(cluster))
+			return nil
+		}
+		items, err := meta.ExtractList(dependents)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "unable to extract objects referencing cluster", "cluster", client.ObjectKeyFromObject(cluster))
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			// Names are only unique within a namespace and kind, so the index
+			// can match references to other clusters.
+			namespace, refKind, _, ok := clusterReferenceOf(obj)
+			if ok && namespace == cluster.GetNamespace() && strings.EqualFold(refKind, kind) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+		}
+		return reqs
+	}
+}
+
+// clusterChanges filters DatabaseCluster and DatabaseClusterReference updates
+// to those that can affect the objects referencing them: changes to the spec
+// and to the cluster's UUID, engine, status or connection details, such as it
+// turning online or moving to new hosts.
+var clusterChanges = builder.WithPredicates(clusterChangePredicate)
+
+var clusterChangePredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+			return true
+		}
+		oldCluster, newCluster := clusterFromObject(e.ObjectOld), clusterFromObject(e.ObjectNew)
+		return oldCluster == nil || newCluster == nil || *oldCluster != *newCluster
+	},
+}
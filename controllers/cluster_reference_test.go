@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var _ = Describe("Cluster references", func() {
+	It("should map a cluster to the objects referencing it", func() {
+		dbRef := mustCreateDatabaseClusterReference()
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-dependents"},
+		}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+		newUser := func(namespace, name string, cluster v1alpha1.ClusterReference) *v1alpha1.DatabaseUser {
+			cluster.APIGroup = &v1alpha1.GroupVersion.Group
+			user := &v1alpha1.DatabaseUser{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: v1alpha1.DatabaseUserSpec{
+					Cluster:  cluster,
+					Username: name,
+				},
+			}
+			Expect(k8sClient.Create(ctx, user)).To(Succeed())
+			return user
+		}
+		newUser("default", "same-namespace-dependent", v1alpha1.ClusterReference{
+			Kind: v1alpha1.DatabaseClusterReferenceKind,
+			Name: dbRef.Name,
+		})
+		newUser(ns.Name, "other-namespace-dependent", v1alpha1.ClusterReference{
+			Kind:      v1alpha1.DatabaseClusterReferenceKind,
+			Name:      dbRef.Name,
+			Namespace: dbRef.Namespace,
+		})
+		// Neither of these reference dbRef, despite matching its name.
+		newUser(ns.Name, "other-namespace-cluster", v1alpha1.ClusterReference{
+			Kind: v1alpha1.DatabaseClusterReferenceKind,
+			Name: dbRef.Name,
+		})
+		newUser("default", "other-kind", v1alpha1.ClusterReference{
+			Kind: v1alpha1.DatabaseClusterKind,
+			Name: dbRef.Name,
+		})
+
+		dependents := clusterDependents(k8sManager.GetClient(), &v1alpha1.DatabaseUserList{})
+		Eventually(func() []reconcile.Request {
+			return dependents(ctx, dbRef)
+		}, timeout, interval).Should(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "same-namespace-dependent"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns.Name, Name: "other-namespace-dependent"}},
+		))
+	})
+
+	It("should pass on changes to a cluster's connection details", func() {
+		oldCluster := &v1alpha1.DatabaseCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "moved", Namespace: "default", Generation: 1},
+			Spec:       v1alpha1.DatabaseClusterSpec{Engine: "pg"},
+			Status: v1alpha1.DatabaseClusterStatus{
+				UUID:           "moved-uuid",
+				Status:         "online",
+				ConnectionHash: "old-hosts",
+			},
+		}
+		newCluster := oldCluster.DeepCopy()
+
+		By("ignoring status updates that don't affect dependents", func() {
+			newCluster.Status.Drift = []v1alpha1.FieldDrift{{Field: "spec.numNodes"}}
+			Expect(clusterChangePredicate.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(BeFalse())
+		})
+
+		By("passing on new connection details", func() {
+			newCluster.Status.ConnectionHash = "new-hosts"
+			Expect(clusterChangePredicate.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(BeTrue())
+		})
+	})
+})
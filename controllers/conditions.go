@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// Reasons for the Ready condition.
+const (
+	readyReasonReconciled      = "Reconciled"
+	readyReasonClusterOnline   = "ClusterOnline"
+	readyReasonClusterNotReady = "ClusterNotReady"
+	readyReasonReconcileFailed = "ReconcileFailed"
+)
+
+// setReadyCondition sets the Ready condition.
+func setReadyCondition(conditions *[]metav1.Condition, generation int64, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setClusterReadyCondition sets the Ready condition from the status of a
+// database in DigitalOcean, which is ready while it's online.
+func setClusterReadyCondition(conditions *[]metav1.Condition, generation int64, dbStatus string) {
+	if dbStatus == "online" {
+		setReadyCondition(conditions, generation, metav1.ConditionTrue, readyReasonClusterOnline, "The cluster is online.")
+		return
+	}
+	setReadyCondition(conditions, generation, metav1.ConditionFalse, readyReasonClusterNotReady, "The cluster is "+dbStatus+".")
+}
+
+// setReconcileFailedCondition sets the Ready condition to false if a
+// reconcile failed. Reconcilers defer it before deferring
+// requeueWhenThrottled, so that it runs afterwards and being throttled
+// doesn't change the condition.
+func setReconcileFailedCondition(conditions *[]metav1.Condition, generation int64, err error) {
+	if err == nil {
+		return
+	}
+	setReadyCondition(conditions, generation, metav1.ConditionFalse, readyReasonReconcileFailed, err.Error())
+}
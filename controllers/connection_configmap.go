@@ -10,7 +10,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func connectionConfigMapForDB(suffix string, owner client.Object, conn *godo.DatabaseConnection) *corev1.ConfigMap {
+func connectionConfigMapForDB(name string, owner client.Object, conn *godo.DatabaseConnection) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -18,7 +18,7 @@ func connectionConfigMapForDB(suffix string, owner client.Object, conn *godo.Dat
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: owner.GetNamespace(),
-			Name:      owner.GetName() + suffix,
+			Name:      name,
 		},
 		Data: map[string]string{
 			"host":     conn.Host,
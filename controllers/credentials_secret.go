@@ -1,13 +1,26 @@
 package controllers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+
 	"github.com/digitalocean/godo"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
 )
 
-func credentialsSecretForDefaultDBUser(owner client.Object, db *godo.Database) *corev1.Secret {
+func credentialsSecretForDefaultDBUser(name string, owner client.Object, db *godo.Database) *corev1.Secret {
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -15,7 +28,7 @@ func credentialsSecretForDefaultDBUser(owner client.Object, db *godo.Database) *
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: owner.GetNamespace(),
-			Name:      owner.GetName() + "-default-credentials",
+			Name:      name,
 		},
 		StringData: map[string]string{
 			"username": db.Connection.User,
@@ -29,23 +42,220 @@ func credentialsSecretForDefaultDBUser(owner client.Object, db *godo.Database) *
 		secret.StringData["private_uri"] = db.PrivateConnection.URI
 	}
 
+	secret.StringData["host"] = db.Connection.Host
+	secret.StringData["port"] = strconv.Itoa(db.Connection.Port)
+	secret.StringData["ssl"] = strconv.FormatBool(db.Connection.SSL)
+	secret.StringData["database"] = db.Connection.Database
+	setServiceBindingType(secret, db.EngineSlug)
+
 	return secret
 }
 
-func credentialsSecretForDBUser(owner client.Object, user *godo.DatabaseUser) *corev1.Secret {
-	return &corev1.Secret{
+// credentialsSecretForDBUser returns the credentials Secret for a user in db.
+// Along with the user's credentials, it contains the details needed to connect
+// to database in db, or to the default database if database is empty.
+func credentialsSecretForDBUser(name string, owner client.Object, db *godo.Database, user *godo.DatabaseUser, database string) *corev1.Secret {
+	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: owner.GetNamespace(),
-			Name:      owner.GetName() + "-credentials",
+			Name:      name,
 		},
 		StringData: map[string]string{
 			"username": user.Name,
 			"password": user.Password,
-			// TODO(awg): Construct uri and private_uri from DB info.
 		},
 	}
+
+	if conn := db.Connection; conn != nil {
+		if database == "" {
+			database = conn.Database
+		}
+		secret.StringData["host"] = conn.Host
+		secret.StringData["port"] = strconv.Itoa(conn.Port)
+		secret.StringData["ssl"] = strconv.FormatBool(conn.SSL)
+		secret.StringData["database"] = database
+		if uri := connectionURI(db.EngineSlug, conn, user, database); uri != "" {
+			secret.StringData["uri"] = uri
+		}
+	}
+	if conn := db.PrivateConnection; conn != nil {
+		if uri := connectionURI(db.EngineSlug, conn, user, database); uri != "" {
+			secret.StringData["private_uri"] = uri
+		}
+	}
+
+	// Kafka users authenticate with a client certificate as well.
+	if user.AccessCert != "" {
+		secret.StringData["access_cert"] = user.AccessCert
+		secret.StringData["access_key"] = user.AccessKey
+	}
+
+	setServiceBindingType(secret, db.EngineSlug)
+
+	return secret
+}
+
+// setServiceBindingType makes a credentials Secret a binding Secret under the
+// Service Binding for Kubernetes specification by setting its type and the
+// type and provider entries.
+func setServiceBindingType(secret *corev1.Secret, engine string) {
+	bindingType := engine
+	switch engine {
+	case "pg":
+		bindingType = "postgresql"
+	case "valkey":
+		// Valkey is compatible with Redis clients.
+		bindingType = "redis"
+	}
+
+	secret.Type = corev1.SecretType("servicebinding.io/" + bindingType)
+	secret.StringData["type"] = bindingType
+	secret.StringData["provider"] = "digitalocean"
+}
+
+// deleteSecretOfOtherType deletes the existing version of secret if it has a
+// different type and is controlled by owner. A Secret's type is immutable, so
+// this lets us re-create credentials Secrets created before they had a
+// Service Binding type. Those Secrets also predate the managed-by label, so
+// they're looked up with apiReader when they aren't cached.
+func deleteSecretOfOtherType(ctx context.Context, c client.Client, apiReader client.Reader, owner client.Object, secret *corev1.Secret) error {
+	existing := &corev1.Secret{}
+	err := getGenerated(ctx, c, apiReader, client.ObjectKeyFromObject(secret), existing)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+	if existing.Type == secret.Type || !metav1.IsControlledBy(existing, owner) {
+		return nil
+	}
+	if err := c.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("deleting secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+	return nil
+}
+
+// serviceBindingForOutputs returns the Service Binding reference to the
+// credentials Secret recorded in outputs, if there is one.
+func serviceBindingForOutputs(outputs v1alpha1.OutputStatus) *corev1.LocalObjectReference {
+	if outputs.CredentialsSecretName == "" {
+		return nil
+	}
+	return &corev1.LocalObjectReference{Name: outputs.CredentialsSecretName}
+}
+
+// connectionURI returns the URI for user to connect to database using conn,
+// in the format expected by clients of the engine. It returns an empty string
+// for engines that don't use connection URIs, such as Kafka.
+func connectionURI(engine string, conn *godo.DatabaseConnection, user *godo.DatabaseUser, database string) string {
+	uri := &url.URL{
+		User: url.UserPassword(user.Name, user.Password),
+		Host: net.JoinHostPort(conn.Host, strconv.Itoa(conn.Port)),
+	}
+	if user.Password == "" {
+		uri.User = url.User(user.Name)
+	}
+	query := url.Values{}
+
+	switch engine {
+	case "pg":
+		uri.Scheme = "postgresql"
+		uri.Path = "/" + database
+		if conn.SSL {
+			query.Set("sslmode", "require")
+		}
+	case "mysql":
+		uri.Scheme = "mysql"
+		uri.Path = "/" + database
+		if conn.SSL {
+			query.Set("ssl-mode", "REQUIRED")
+		}
+	case "redis", "valkey":
+		uri.Scheme = "redis"
+		if conn.SSL {
+			uri.Scheme = "rediss"
+		}
+	case "mongodb":
+		// MongoDB clusters are addressed through DNS SRV records, which
+		// don't allow a port.
+		uri.Scheme = "mongodb+srv"
+		uri.Host = conn.Host
+		uri.Path = "/" + database
+		query.Set("authSource", "admin")
+		if conn.SSL {
+			query.Set("tls", "true")
+		}
+	case "opensearch":
+		uri.Scheme = "http"
+		if conn.SSL {
+			uri.Scheme = "https"
+		}
+	default:
+		return ""
+	}
+
+	uri.RawQuery = query.Encode()
+	return uri.String()
+}
+
+// applySecretTemplate adds the keys of secretTemplate to a credentials Secret
+// for user in db, rendering each template with the user's connection details.
+func applySecretTemplate(ctx context.Context, godoClient *godo.Client, secret *corev1.Secret, secretTemplate map[string]string, db *godo.Database, user, password, database string) error {
+	if len(secretTemplate) == 0 {
+		return nil
+	}
+
+	data := &v1alpha1.SecretTemplateData{
+		User:     user,
+		Password: password,
+		Database: database,
+		SSLMode:  "disable",
+	}
+	// The CA takes another API call, so it's only fetched for templates that
+	// use it.
+	usesCA := slices.ContainsFunc(slices.Collect(maps.Keys(secretTemplate)), func(key string) bool {
+		return v1alpha1.SecretTemplateUsesCA(key, secretTemplate[key])
+	})
+	if usesCA {
+		ca, _, err := godoClient.Databases.GetCA(ctx, db.ID)
+		if err != nil {
+			return fmt.Errorf("getting database CA: %w", err)
+		}
+		data.CA = string(ca.Certificate)
+	}
+	if conn := db.Connection; conn != nil {
+		data.Host = conn.Host
+		data.Port = conn.Port
+		if data.Database == "" {
+			data.Database = conn.Database
+		}
+		if conn.SSL {
+			data.SSLMode = "require"
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(secretTemplate)) {
+		value, err := v1alpha1.ExecuteSecretTemplate(key, secretTemplate[key], data)
+		if err != nil {
+			return fmt.Errorf("executing secret template for key %q: %w", key, err)
+		}
+		secret.StringData[key] = value
+	}
+
+	return nil
+}
+
+// secretDataHash returns a hash of a Secret's StringData, which changes
+// whenever the credentials in it do.
+func secretDataHash(secret *corev1.Secret) string {
+	h := sha256.New()
+	for _, k := range slices.Sorted(maps.Keys(secret.StringData)) {
+		h.Write([]byte(k + "=" + secret.StringData[k] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// databaseEventTimeLayouts are the layouts the API has been seen to use for
+// event creation times.
+var databaseEventTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05MST",
+	"2006-01-02 15:04:05",
+}
+
+// emitDatabaseEvents emits the database's events that are newer than the
+// cursor as Kubernetes Events on obj and returns the updated cursor. When
+// there is no cursor yet, only events created after obj are emitted, so that a
+// new reference to an old cluster doesn't replay its whole history. Events are
+// best-effort: failing to list them is logged rather than returned.
+func emitDatabaseEvents(ctx context.Context, godoClient *godo.Client, recorder events.EventRecorder, obj client.Object, dbUUID string, cursor *v1alpha1.DatabaseEventsCursor) *v1alpha1.DatabaseEventsCursor {
+	ll := log.FromContext(ctx).WithValues("db_uuid", dbUUID)
+
+	if recorder == nil {
+		return cursor
+	}
+
+	dbEvents, _, err := godoClient.Databases.ListDatabaseEvents(ctx, dbUUID, nil)
+	if err != nil {
+		ll.Error(err, "unable to list database events")
+		return cursor
+	}
+
+	if cursor == nil {
+		cursor = &v1alpha1.DatabaseEventsCursor{
+			LastEventTime: obj.GetCreationTimestamp(),
+		}
+	} else {
+		cursor = cursor.DeepCopy()
+	}
+
+	type timedEvent struct {
+		godo.DatabaseEvent
+		created time.Time
+	}
+	var newEvents []timedEvent
+	for _, dbEvent := range dbEvents {
+		created, ok := parseDatabaseEventTime(dbEvent.CreateTime)
+		if !ok {
+			ll.Info("skipping database event with unparseable time", "event_id", dbEvent.ID, "create_time", dbEvent.CreateTime)
+			continue
+		}
+		if created.Before(cursor.LastEventTime.Time) {
+			continue
+		}
+		if created.Equal(cursor.LastEventTime.Time) && slices.Contains(cursor.LastEventIDs, dbEvent.ID) {
+			continue
+		}
+		newEvents = append(newEvents, timedEvent{DatabaseEvent: dbEvent, created: created})
+	}
+
+	// Emit events oldest first so they show up in order.
+	sort.SliceStable(newEvents, func(i, j int) bool {
+		return newEvents[i].created.Before(newEvents[j].created)
+	})
+
+	for _, dbEvent := range newEvents {
+		eventType := corev1.EventTypeNormal
+		if strings.Contains(dbEvent.EventType, "fail") {
+			eventType = corev1.EventTypeWarning
+		}
+		recorder.Eventf(obj, nil, eventType, databaseEventReason(dbEvent.EventType), "DatabaseEvent",
+			"Database event %s at %s", dbEvent.EventType, dbEvent.CreateTime)
+
+		if dbEvent.created.After(cursor.LastEventTime.Time) {
+			cursor.LastEventTime = metav1.NewTime(dbEvent.created)
+			cursor.LastEventIDs = nil
+		}
+		cursor.LastEventIDs = append(cursor.LastEventIDs, dbEvent.ID)
+	}
+
+	return cursor
+}
+
+// parseDatabaseEventTime parses the creation time of a database event.
+func parseDatabaseEventTime(s string) (time.Time, bool) {
+	for _, layout := range databaseEventTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			// Kubernetes serializes times with second precision, so truncate
+			// to keep comparisons with the cursor stable.
+			return t.UTC().Truncate(time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// databaseEventReason converts a database event type such as
+// cluster_maintenance_perform into an Event reason such as
+// ClusterMaintenancePerform.
+func databaseEventReason(eventType string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(eventType, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if b.Len() == 0 {
+		return "DatabaseEvent"
+	}
+	return b.String()
+}
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -32,6 +34,7 @@ import (
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/godo"
 	"github.com/google/go-cmp/cmp"
 )
@@ -43,14 +46,29 @@ const (
 // DatabaseClusterReconciler reconciles a DatabaseCluster object
 type DatabaseClusterReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	GodoClient *godo.Client
+	// APIReader reads from the API server rather than the cache. It's used
+	// to find generated objects that predate the managed-by label, which
+	// aren't cached until they're next applied.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+	// DriftPolicy is the drift policy for clusters that don't set one.
+	// Defaults to Observe.
+	DriftPolicy string
+	// MetricsRotations is shared with the DatabaseClusterReference
+	// reconciler, so that rotating the metrics credentials updates the
+	// metrics Secrets of every cluster and reference in the account.
+	MetricsRotations *MetricsCredentialsRotations
 }
 
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusters/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups="",resources=services;endpoints,verbs=create;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs;servicemonitors,verbs=create;patch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -105,70 +123,108 @@ func (r *DatabaseClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
 	}()
+	defer func() { setReconcileFailedCondition(&cluster.Status.Conditions, cluster.Generation, retErr) }()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, cluster.Namespace, cluster.Spec.ProviderConfigRef)
+	if err != nil {
+		if inDeletion && releaseWithoutCredentials(ctx, r.Recorder, &cluster, err) {
+			return result, nil
+		}
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
 
 	if inDeletion {
 		ll.Info("deleting DatabaseCluster")
-		result, err = r.reconcileDeletedDB(ctx, &cluster)
+		result, err = r.reconcileDeletedDB(ctx, godoClient, &cluster)
 	} else if cluster.Status.UUID != "" {
 		ll.Info("reconciling existing DatabaseCluster")
-		result, err = r.reconcileExistingDB(ctx, &cluster)
+		result, err = r.reconcileExistingDB(ctx, godoClient, &cluster)
 	} else {
 		ll.Info("reconciling new DatabaseCluster")
-		result, err = r.reconcileNewDB(ctx, &cluster)
+		result, err = r.reconcileNewDB(ctx, godoClient, &cluster)
 	}
 
 	return result, err
 }
 
-func (r *DatabaseClusterReconciler) reconcileNewDB(ctx context.Context, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
+func (r *DatabaseClusterReconciler) reconcileNewDB(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 
 	createReq := cluster.Spec.ToGodoCreateRequest()
-	db, _, err := r.GodoClient.Databases.Create(ctx, createReq)
+	db, _, err := godoClient.Databases.Create(ctx, createReq)
+	recordClusterOperation("create", err)
 	if err != nil {
 		ll.Error(err, "unable to create DB")
-		return ctrl.Result{}, fmt.Errorf("creating DB cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("creating DB cluster: %w", err)
 	}
 
 	controllerutil.AddFinalizer(cluster, finalizerName)
 	cluster.Status.UUID = db.ID
 	cluster.Status.CreatedAt = metav1.NewTime(db.CreatedAt)
 	cluster.Status.Status = db.Status
+	cluster.Status.ConnectionHash = connectionHash(db)
+	setClusterReadyCondition(&cluster.Status.Conditions, cluster.Generation, db.Status)
 
-	err = r.ensureOwnedObjects(ctx, cluster, db)
+	err = r.ensureOwnedObjects(ctx, godoClient, cluster, db)
 	if err != nil {
 		ll.Error(err, "unable to ensure DB-related objects")
-		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %v", err)
+		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %w", err)
 	}
 
 	return ctrl.Result{RequeueAfter: time.Minute}, nil
 }
 
-func (r *DatabaseClusterReconciler) reconcileExistingDB(ctx context.Context, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
+func (r *DatabaseClusterReconciler) reconcileExistingDB(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 	ll = ll.WithValues("db_uuid", cluster.Status.UUID)
 
-	db, _, err := r.GodoClient.Databases.Get(ctx, cluster.Status.UUID)
+	db, _, err := godoClient.Databases.Get(ctx, cluster.Status.UUID)
 	if err != nil {
 		ll.Error(err, "unable to fetch existing DB")
-		return ctrl.Result{}, fmt.Errorf("getting existing DB cluster: %v", err)
-	}
-
-	// Resize if either of the size parameters in the spec has changed.
-	if db.NumNodes != int(cluster.Spec.NumNodes) || db.SizeSlug != cluster.Spec.Size {
-		ll.Info("resizing database",
-			"current_num_nodes", db.NumNodes, "desired_num_nodes", cluster.Spec.NumNodes,
-			"current_size", db.SizeSlug, "desired_size", cluster.Spec.Size)
-		_, err = r.GodoClient.Databases.Resize(ctx, cluster.Status.UUID, &godo.DatabaseResizeRequest{
-			SizeSlug: cluster.Spec.Size,
-			NumNodes: int(cluster.Spec.NumNodes),
-		})
-		if err != nil {
-			ll.Error(err, "unable to resize existing DB")
-			return ctrl.Result{}, fmt.Errorf("resizing existing DB cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("getting existing DB cluster: %w", err)
+	}
+	cluster.Status.ConnectionHash = connectionHash(db)
+	setClusterReadyCondition(&cluster.Status.Conditions, cluster.Generation, db.Status)
+
+	drifts, err := detectClusterDrift(ctx, godoClient, cluster, db)
+	if err != nil {
+		ll.Error(err, "unable to detect drift")
+		return ctrl.Result{}, fmt.Errorf("detecting drift: %w", err)
+	}
+	cluster.Status.Drift = driftedFields(drifts)
+	// The drift policy only governs changes made in DigitalOcean. Changes to
+	// the spec, including settings that can't be set at creation, are always
+	// applied.
+	enforce := r.driftPolicy(cluster) == v1alpha1.DriftPolicyEnforce
+	specChanged := cluster.Status.AppliedGeneration != cluster.Generation
+	if enforce || specChanged {
+		setDriftedCondition(&cluster.Status.Conditions, cluster.Generation, cluster.Status.Drift, driftReasonCorrecting)
+	} else {
+		setDriftedCondition(&cluster.Status.Conditions, cluster.Generation, cluster.Status.Drift, driftReasonObserved)
+	}
+	// The database can only be changed while it's online, e.g. not while it's
+	// still being created or resized.
+	corrected := false
+	if db.Status == "online" {
+		if len(drifts) > 0 && (enforce || specChanged) {
+			for _, drift := range drifts {
+				ll.Info("correcting drift", "fields", drift.fields, "spec_changed", specChanged)
+				if err := drift.correct(ctx); err != nil {
+					ll.Error(err, "unable to correct drift")
+					return ctrl.Result{}, fmt.Errorf("correcting drift: %w", err)
+				}
+			}
+			reason, action := "DriftCorrected", "CorrectDrift"
+			if specChanged {
+				reason, action = "SpecApplied", "ApplySpec"
+			}
+			r.Recorder.Eventf(cluster, nil, corev1.EventTypeNormal, reason, action,
+				"Changed %d settings to match the spec", len(cluster.Status.Drift))
+			corrected = true
 		}
-		// Requeue immediately so we pick up status updates.
-		return ctrl.Result{Requeue: true}, nil
+		cluster.Status.AppliedGeneration = cluster.Generation
 	}
 
 	// Update status. By default we'll reconcile again in a minute, but if the
@@ -179,7 +235,7 @@ func (r *DatabaseClusterReconciler) reconcileExistingDB(ctx context.Context, clu
 		cluster.Status.Status = db.Status
 		if err := r.Status().Update(ctx, cluster); err != nil {
 			ll.Error(err, "unable to update DatabaseCluster status")
-			return ctrl.Result{}, fmt.Errorf("updating status: %v", err)
+			return ctrl.Result{}, fmt.Errorf("updating status: %w", err)
 		}
 		switch db.Status {
 		case "online":
@@ -188,17 +244,41 @@ func (r *DatabaseClusterReconciler) reconcileExistingDB(ctx context.Context, clu
 			requeueTime = 30 * time.Second
 		}
 	}
+	if corrected {
+		// Reconcile again soon to pick up the changes.
+		requeueTime = 30 * time.Second
+	}
+
+	err = r.rotateMetricsCredentials(ctx, godoClient, cluster)
+	if err != nil {
+		ll.Error(err, "unable to rotate metrics credentials")
+		return ctrl.Result{}, fmt.Errorf("rotating metrics credentials: %w", err)
+	}
 
-	err = r.ensureOwnedObjects(ctx, cluster, db)
+	err = r.ensureOwnedObjects(ctx, godoClient, cluster, db)
 	if err != nil {
 		ll.Error(err, "unable to ensure DB-related objects")
-		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %v", err)
+		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %w", err)
 	}
 
+	cluster.Status.EventsCursor = emitDatabaseEvents(ctx, godoClient, r.Recorder, cluster, cluster.Status.UUID, cluster.Status.EventsCursor)
+
 	return ctrl.Result{RequeueAfter: requeueTime}, nil
 }
 
-func (r *DatabaseClusterReconciler) reconcileDeletedDB(ctx context.Context, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
+// driftPolicy returns the drift policy that applies to a cluster.
+func (r *DatabaseClusterReconciler) driftPolicy(cluster *v1alpha1.DatabaseCluster) string {
+	switch {
+	case cluster.Spec.DriftPolicy != "":
+		return cluster.Spec.DriftPolicy
+	case r.DriftPolicy != "":
+		return r.DriftPolicy
+	default:
+		return v1alpha1.DriftPolicyObserve
+	}
+}
+
+func (r *DatabaseClusterReconciler) reconcileDeletedDB(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 	ll = ll.WithValues("db_uuid", cluster.Status.UUID)
 
@@ -208,39 +288,125 @@ func (r *DatabaseClusterReconciler) reconcileDeletedDB(ctx context.Context, clus
 		return ctrl.Result{}, nil
 	}
 
-	_, err := r.GodoClient.Databases.Delete(ctx, cluster.Status.UUID)
+	_, err := godoClient.Databases.Delete(ctx, cluster.Status.UUID)
+	recordClusterOperation("delete", err)
 	if err != nil {
 		ll.Error(err, "unable to delete DB")
-		return ctrl.Result{}, fmt.Errorf("deleting DB: %v", err)
+		return ctrl.Result{}, fmt.Errorf("deleting DB: %w", err)
 	}
 	controllerutil.RemoveFinalizer(cluster, finalizerName)
 
 	return ctrl.Result{}, nil
 }
 
-func (r *DatabaseClusterReconciler) ensureOwnedObjects(ctx context.Context, cluster *v1alpha1.DatabaseCluster, db *godo.Database) error {
-	objs := []client.Object{}
+func (r *DatabaseClusterReconciler) ensureOwnedObjects(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster, db *godo.Database) error {
+	output := cluster.Spec.Output
+	if output == nil {
+		output = &v1alpha1.ClusterOutput{}
+	}
+
+	var outputs []generatedOutput
 	if db.Connection != nil {
-		objs = append(objs, connectionConfigMapForDB("-connection", cluster, db.Connection))
+		cm := connectionConfigMapForDB(outputName(output.ConnectionConfigMapName, cluster, connectionSuffix), cluster, db.Connection)
+		outputs = append(outputs, newGeneratedOutput(cm, &cluster.Status.Outputs.ConnectionConfigMapName, cluster, connectionSuffix))
 	}
 	if db.PrivateConnection != nil {
-		objs = append(objs, connectionConfigMapForDB("-private-connection", cluster, db.PrivateConnection))
+		cm := connectionConfigMapForDB(outputName(output.PrivateConnectionConfigMapName, cluster, privateConnectionSuffix), cluster, db.PrivateConnection)
+		outputs = append(outputs, newGeneratedOutput(cm, &cluster.Status.Outputs.PrivateConnectionConfigMapName, cluster, privateConnectionSuffix))
 	}
 
 	if db.Connection != nil && db.Connection.Password != "" {
 		// MongoDB doesn't return the default user password with the DB except
 		// on creation. Don't update the credentials if the password is empty,
 		// but create the secret if we have the password.
-		objs = append(objs, credentialsSecretForDefaultDBUser(cluster, db))
+		secret := credentialsSecretForDefaultDBUser(outputName(output.CredentialsSecretName, cluster, defaultCredentialsSuffix), cluster, db)
+		if err := applySecretTemplate(ctx, godoClient, secret, cluster.Spec.SecretTemplate, db, db.Connection.User, db.Connection.Password, ""); err != nil {
+			return err
+		}
+		if err := deleteSecretOfOtherType(ctx, r.Client, r.APIReader, cluster, secret); err != nil {
+			return err
+		}
+		outputs = append(outputs, newGeneratedOutput(secret, &cluster.Status.Outputs.CredentialsSecretName, cluster, defaultCredentialsSuffix))
 	}
 
+	objs := []client.Object{}
+	for _, out := range outputs {
+		setOutputMetadata(out.obj, &output.OutputMetadata)
+		objs = append(objs, out.obj)
+	}
+
+	metricsObjs, err := metricsObjectsForDB(ctx, r.Client, godoClient, cluster, db)
+	if err != nil {
+		return fmt.Errorf("building metrics objects: %w", err)
+	}
+	objs = append(objs, metricsObjs...)
+
 	for _, obj := range objs {
+		setManagedByLabel(obj)
 		controllerutil.SetControllerReference(cluster, obj, r.Scheme)
 		if err := r.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner("do-operator")); err != nil {
 			return fmt.Errorf("applying object %s: %s", client.ObjectKeyFromObject(obj), err)
 		}
 	}
 
+	for _, out := range outputs {
+		if err := out.record(ctx, r.Client, r.APIReader, cluster); err != nil {
+			return err
+		}
+	}
+	cluster.Status.Binding = serviceBindingForOutputs(cluster.Status.Outputs)
+
+	return nil
+}
+
+// rotateMetricsCredentials rotates the metrics credentials if rotation is
+// enabled and the rotation interval has passed since they were last rotated.
+// The first time rotation is enabled, we only record the time so that enabling
+// rotation doesn't immediately rotate credentials shared with other clusters.
+// Since the credentials are shared, a rotation by another cluster using the
+// same client counts as a rotation of this cluster's credentials.
+func (r *DatabaseClusterReconciler) rotateMetricsCredentials(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) error {
+	interval := cluster.Spec.MetricsCredentialsRotationInterval
+	if interval == nil {
+		cluster.Status.MetricsCredentialsRotatedAt = nil
+		return nil
+	}
+
+	now := metav1.Now()
+	rotatedAt := cluster.Status.MetricsCredentialsRotatedAt
+	if last, ok := r.MetricsRotations.lastRotated(godoClient); ok && (rotatedAt == nil || last.After(rotatedAt.Time)) {
+		rotatedAt = &metav1.Time{Time: last}
+		cluster.Status.MetricsCredentialsRotatedAt = rotatedAt
+	}
+	if rotatedAt == nil {
+		cluster.Status.MetricsCredentialsRotatedAt = &now
+		return nil
+	}
+	if now.Before(&metav1.Time{Time: rotatedAt.Add(interval.Duration)}) {
+		return nil
+	}
+
+	creds, _, err := godoClient.Databases.GetMetricsCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("getting metrics credentials: %w", err)
+	}
+	password, err := generateMetricsPassword()
+	if err != nil {
+		return err
+	}
+	_, err = godoClient.Databases.UpdateMetricsCredentials(ctx, &godo.DatabaseUpdateMetricsCredentialsRequest{
+		Credentials: &godo.DatabaseMetricsCredentials{
+			BasicAuthUsername: creds.BasicAuthUsername,
+			BasicAuthPassword: password,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("updating metrics credentials: %w", err)
+	}
+	log.FromContext(ctx).Info("rotated metrics credentials")
+	cluster.Status.MetricsCredentialsRotatedAt = &now
+	r.MetricsRotations.rotated(godoClient, now.Time)
+
 	return nil
 }
 
@@ -248,5 +414,10 @@ func (r *DatabaseClusterReconciler) ensureOwnedObjects(ctx context.Context, clus
 func (r *DatabaseClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databasesv1alpha1.DatabaseCluster{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		WatchesRawSource(r.MetricsRotations.source(metricsSecretOwners(r.Client, r.GodoClients, &databasesv1alpha1.DatabaseClusterList{}, func(obj client.Object) *corev1.LocalObjectReference {
+			return obj.(*databasesv1alpha1.DatabaseCluster).Spec.ProviderConfigRef
+		}))).
 		Complete(r)
 }
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"time"
+
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	"github.com/digitalocean/do-operator/fakegodo"
 	. "github.com/onsi/ginkgo"
@@ -116,8 +118,56 @@ var _ = Describe("DatabaseCluster controller", func() {
 				}, timeout, interval).Should(Succeed())
 				Expect(secret.OwnerReferences).To(ContainElement(dbClusterOwnerReference))
 				Expect(secret.Data["uri"]).NotTo(BeEmpty())
+				Expect(secret.Data).To(HaveKey("ssl"))
+				Expect(secret.Data["username"]).NotTo(BeEmpty())
+				Expect(secret.Data["password"]).NotTo(BeEmpty())
+				Expect(secret.Type).To(Equal(corev1.SecretType("servicebinding.io/mongodb")))
+				Expect(string(secret.Data["type"])).To(Equal("mongodb"))
+				Expect(string(secret.Data["provider"])).To(Equal("digitalocean"))
+			})
+
+			By("ensuring the credentials Secret is exposed as a Service Binding", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, dbClusterLookupKey, createdDBCluster)).To(Succeed())
+					g.Expect(createdDBCluster.Status.Binding).NotTo(BeNil())
+					g.Expect(createdDBCluster.Status.Binding.Name).To(Equal("db-crd-default-credentials"))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("ensuring the metrics credentials Secret is created", func() {
+				secretKey := types.NamespacedName{
+					Name:      "db-crd-metrics-credentials",
+					Namespace: "default",
+				}
+				secret := &corev1.Secret{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, secretKey, secret)
+				}, timeout, interval).Should(Succeed())
+				Expect(secret.OwnerReferences).To(ContainElement(dbClusterOwnerReference))
 				Expect(secret.Data["username"]).NotTo(BeEmpty())
 				Expect(secret.Data["password"]).NotTo(BeEmpty())
+				Expect(secret.Data["ca.crt"]).NotTo(BeEmpty())
+			})
+
+			By("ensuring the metrics Service and Endpoints are created", func() {
+				metricsKey := types.NamespacedName{
+					Name:      "db-crd-metrics",
+					Namespace: "default",
+				}
+				svc := &corev1.Service{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, metricsKey, svc)
+				}, timeout, interval).Should(Succeed())
+				Expect(svc.OwnerReferences).To(ContainElement(dbClusterOwnerReference))
+				Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+				Expect(svc.Annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+
+				endpoints := &corev1.Endpoints{}
+				Expect(k8sClient.Get(ctx, metricsKey, endpoints)).To(Succeed())
+				Expect(endpoints.OwnerReferences).To(ContainElement(dbClusterOwnerReference))
+				Expect(endpoints.Subsets).To(HaveLen(1))
+				Expect(endpoints.Subsets[0].Addresses).To(ConsistOf(corev1.EndpointAddress{IP: "192.0.2.10"}))
+				Expect(endpoints.Subsets[0].Ports[0].Port).To(Equal(int32(9273)))
 			})
 
 			By("ensuring the DatabaseCluster status gets refreshed later", func() {
@@ -166,5 +216,70 @@ var _ = Describe("DatabaseCluster controller", func() {
 				}, timeout, interval).ShouldNot(Succeed())
 			})
 		})
+
+		It("should rotate the metrics credentials", func() {
+			var (
+				dbCluster          = mustCreateDatabaseClusterWithEngine("pg")
+				dbClusterLookupKey = client.ObjectKeyFromObject(dbCluster)
+				// otherCluster doesn't rotate the credentials, but shares them.
+				otherCluster = mustCreateDatabaseClusterWithEngine("pg")
+				oldPassword  string
+			)
+
+			By("enabling metrics credentials rotation", func() {
+				creds, _, err := fakeDatabasesService.GetMetricsCredentials(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				oldPassword = creds.BasicAuthPassword
+
+				updatedCluster := dbCluster.DeepCopy()
+				updatedCluster.Spec.MetricsCredentialsRotationInterval = &metav1.Duration{Duration: time.Hour}
+				Expect(k8sClient.Patch(ctx, updatedCluster, client.MergeFrom(dbCluster))).To(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, dbClusterLookupKey, dbCluster)).To(Succeed())
+					g.Expect(dbCluster.Status.MetricsCredentialsRotatedAt).NotTo(BeNil())
+				}, timeout, interval).Should(Succeed())
+
+				// Enabling rotation shouldn't rotate the credentials right away.
+				creds, _, err = fakeDatabasesService.GetMetricsCredentials(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(creds.BasicAuthPassword).To(Equal(oldPassword))
+			})
+
+			By("making the last rotation older than the rotation interval", func() {
+				updatedCluster := dbCluster.DeepCopy()
+				updatedCluster.Status.MetricsCredentialsRotatedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				Expect(k8sClient.Status().Patch(ctx, updatedCluster, client.MergeFrom(dbCluster))).To(Succeed())
+			})
+
+			By("ensuring the credentials are rotated", func() {
+				Eventually(func(g Gomega) {
+					creds, _, err := fakeDatabasesService.GetMetricsCredentials(ctx)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(creds.BasicAuthPassword).NotTo(Equal(oldPassword))
+
+					secret := &corev1.Secret{}
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+						Name:      dbCluster.Name + "-metrics-credentials",
+						Namespace: dbCluster.Namespace,
+					}, secret)).To(Succeed())
+					g.Expect(string(secret.Data["password"])).To(Equal(creds.BasicAuthPassword))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("ensuring the other cluster's metrics Secret is updated", func() {
+				creds, _, err := fakeDatabasesService.GetMetricsCredentials(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func(g Gomega) {
+					secret := &corev1.Secret{}
+					g.Expect(k8sClient.Get(ctx, types.NamespacedName{
+						Name:      otherCluster.Name + "-metrics-credentials",
+						Namespace: otherCluster.Namespace,
+					}, secret)).To(Succeed())
+					g.Expect(string(secret.Data["password"])).To(Equal(creds.BasicAuthPassword))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
 	})
 })
@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -32,6 +34,7 @@ import (
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/godo"
 	"github.com/google/go-cmp/cmp"
 )
@@ -39,13 +42,26 @@ import (
 // DatabaseClusterReferenceReconciler reconciles a DatabaseClusterReference object
 type DatabaseClusterReferenceReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	GodoClient *godo.Client
+	// APIReader reads from the API server rather than the cache. It's used
+	// to find generated objects that predate the managed-by label, which
+	// aren't cached until they're next applied.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+	// MetricsRotations is shared with the DatabaseCluster reconciler, which
+	// rotates the metrics credentials, so that the metrics Secrets of
+	// references are updated after each rotation.
+	MetricsRotations *MetricsCredentialsRotations
 }
 
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusterreferences,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusterreferences/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseclusterreferences/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups="",resources=services;endpoints,verbs=create;patch
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs;servicemonitors,verbs=create;patch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 var (
 	// clusterReferenceRefereshTime is how often we refresh the
@@ -97,11 +113,19 @@ func (r *DatabaseClusterReferenceReconciler) Reconcile(ctx context.Context, req
 
 		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
 	}()
+	defer func() { setReconcileFailedCondition(&ref.Status.Conditions, ref.Generation, retErr) }()
 
-	db, _, err := r.GodoClient.Databases.Get(ctx, ref.Spec.UUID)
+	godoClient, err := r.GodoClients.ClientFor(ctx, ref.Namespace, ref.Spec.ProviderConfigRef)
+	if err != nil {
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	db, _, err := godoClient.Databases.Get(ctx, ref.Spec.UUID)
 	if err != nil {
 		ll.Error(err, "unable to fetch existing DB")
-		return ctrl.Result{}, fmt.Errorf("getting existing DB cluster: %v", err)
+		return ctrl.Result{}, fmt.Errorf("getting existing DB cluster: %w", err)
 	}
 
 	ref.Status.Engine = db.EngineSlug
@@ -112,39 +136,74 @@ func (r *DatabaseClusterReferenceReconciler) Reconcile(ctx context.Context, req
 	ref.Status.Region = db.RegionSlug
 	ref.Status.Status = db.Status
 	ref.Status.CreatedAt = metav1.NewTime(db.CreatedAt)
+	ref.Status.ConnectionHash = connectionHash(db)
+	setClusterReadyCondition(&ref.Status.Conditions, ref.Generation, db.Status)
 
-	err = r.ensureOwnedObjects(ctx, &ref, db)
+	err = r.ensureOwnedObjects(ctx, godoClient, &ref, db)
 	if err != nil {
 		ll.Error(err, "unable to ensure DB-related objects")
-		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %v", err)
+		return ctrl.Result{}, fmt.Errorf("ensuring DB-related objects: %w", err)
 	}
 
+	ref.Status.EventsCursor = emitDatabaseEvents(ctx, godoClient, r.Recorder, &ref, db.ID, ref.Status.EventsCursor)
+
 	return ctrl.Result{RequeueAfter: clusterReferenceRefreshTime}, nil
 }
 
-func (r *DatabaseClusterReferenceReconciler) ensureOwnedObjects(ctx context.Context, cluster *v1alpha1.DatabaseClusterReference, db *godo.Database) error {
-	objs := []client.Object{}
+func (r *DatabaseClusterReferenceReconciler) ensureOwnedObjects(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseClusterReference, db *godo.Database) error {
+	output := cluster.Spec.Output
+	if output == nil {
+		output = &v1alpha1.ClusterOutput{}
+	}
+
+	var outputs []generatedOutput
 	if db.Connection != nil {
-		objs = append(objs, connectionConfigMapForDB("-connection", cluster, db.Connection))
+		cm := connectionConfigMapForDB(outputName(output.ConnectionConfigMapName, cluster, connectionSuffix), cluster, db.Connection)
+		outputs = append(outputs, newGeneratedOutput(cm, &cluster.Status.Outputs.ConnectionConfigMapName, cluster, connectionSuffix))
 	}
 	if db.PrivateConnection != nil {
-		objs = append(objs, connectionConfigMapForDB("-private-connection", cluster, db.PrivateConnection))
+		cm := connectionConfigMapForDB(outputName(output.PrivateConnectionConfigMapName, cluster, privateConnectionSuffix), cluster, db.PrivateConnection)
+		outputs = append(outputs, newGeneratedOutput(cm, &cluster.Status.Outputs.PrivateConnectionConfigMapName, cluster, privateConnectionSuffix))
 	}
 
 	if db.Connection != nil && db.Connection.Password != "" {
 		// MongoDB doesn't return the default user password with the DB except
 		// on creation. Don't update the credentials if the password is empty,
 		// but create the secret if we have the password.
-		objs = append(objs, credentialsSecretForDefaultDBUser(cluster, db))
+		secret := credentialsSecretForDefaultDBUser(outputName(output.CredentialsSecretName, cluster, defaultCredentialsSuffix), cluster, db)
+		if err := deleteSecretOfOtherType(ctx, r.Client, r.APIReader, cluster, secret); err != nil {
+			return err
+		}
+		outputs = append(outputs, newGeneratedOutput(secret, &cluster.Status.Outputs.CredentialsSecretName, cluster, defaultCredentialsSuffix))
 	}
 
+	objs := []client.Object{}
+	for _, out := range outputs {
+		setOutputMetadata(out.obj, &output.OutputMetadata)
+		objs = append(objs, out.obj)
+	}
+
+	metricsObjs, err := metricsObjectsForDB(ctx, r.Client, godoClient, cluster, db)
+	if err != nil {
+		return fmt.Errorf("building metrics objects: %w", err)
+	}
+	objs = append(objs, metricsObjs...)
+
 	for _, obj := range objs {
+		setManagedByLabel(obj)
 		controllerutil.SetControllerReference(cluster, obj, r.Scheme)
 		if err := r.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner("do-operator")); err != nil {
 			return fmt.Errorf("applying object %s: %s", client.ObjectKeyFromObject(obj), err)
 		}
 	}
 
+	for _, out := range outputs {
+		if err := out.record(ctx, r.Client, r.APIReader, cluster); err != nil {
+			return err
+		}
+	}
+	cluster.Status.Binding = serviceBindingForOutputs(cluster.Status.Outputs)
+
 	return nil
 }
 
@@ -152,5 +211,10 @@ func (r *DatabaseClusterReferenceReconciler) ensureOwnedObjects(ctx context.Cont
 func (r *DatabaseClusterReferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databasesv1alpha1.DatabaseClusterReference{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		WatchesRawSource(r.MetricsRotations.source(metricsSecretOwners(r.Client, r.GodoClients, &databasesv1alpha1.DatabaseClusterReferenceList{}, func(obj client.Object) *corev1.LocalObjectReference {
+			return obj.(*databasesv1alpha1.DatabaseClusterReference).Spec.ProviderConfigRef
+		}))).
 		Complete(r)
 }
@@ -9,10 +9,13 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("DatabaseClusterReference controller", func() {
@@ -96,6 +99,7 @@ var _ = Describe("DatabaseClusterReference controller", func() {
 					g.Expect(createdDBRef.Status.Version).To(Equal(dbVersion))
 					g.Expect(createdDBRef.Status.Status).To(Equal(fakegodo.OnlineStatus))
 					g.Expect(createdDBRef.Status.CreatedAt.IsZero()).NotTo(BeTrue())
+					g.Expect(meta.IsStatusConditionTrue(createdDBRef.Status.Conditions, v1alpha1.ConditionTypeReady)).To(BeTrue())
 				}, timeout, interval).Should(Succeed())
 			})
 
@@ -126,6 +130,7 @@ var _ = Describe("DatabaseClusterReference controller", func() {
 				}, timeout, interval).Should(Succeed())
 				Expect(secret.OwnerReferences).To(ContainElement(dbRefOwnerReference))
 				Expect(secret.Data["uri"]).NotTo(BeEmpty())
+				Expect(secret.Data).To(HaveKey("ssl"))
 				Expect(secret.Data["username"]).NotTo(BeEmpty())
 				Expect(secret.Data["password"]).NotTo(BeEmpty())
 			})
@@ -162,5 +167,78 @@ var _ = Describe("DatabaseClusterReference controller", func() {
 				}, timeout, interval).Should(Succeed())
 			})
 		})
+
+		It("should emit database events as Kubernetes Events", func() {
+			var (
+				dbRefLookupKey = types.NamespacedName{
+					Name:      "dbref-events",
+					Namespace: "default",
+				}
+				createdDBRef = &v1alpha1.DatabaseClusterReference{}
+				dbUUID       string
+			)
+
+			By("creating the database in godo", func() {
+				db, _, err := fakeDatabasesService.Create(ctx, &godo.DatabaseCreateRequest{
+					Name:       "events-are-cool",
+					EngineSlug: dbEngine,
+					Version:    dbVersion,
+					SizeSlug:   dbSize,
+					Region:     dbRegion,
+					NumNodes:   dbNumNodes,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				dbUUID = db.ID
+			})
+
+			By("creating the DatabaseClusterReference object", func() {
+				dbRef := &v1alpha1.DatabaseClusterReference{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.DatabaseClusterReferenceKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dbRefLookupKey.Name,
+						Namespace: dbRefLookupKey.Namespace,
+					},
+					Spec: v1alpha1.DatabaseClusterReferenceSpec{
+						UUID: dbUUID,
+					},
+				}
+				Expect(k8sClient.Create(ctx, dbRef)).To(Succeed())
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, dbRefLookupKey, createdDBRef)).To(Succeed())
+					g.Expect(createdDBRef.Status.EventsCursor).NotTo(BeNil())
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("recording a failover event in godo", func() {
+				Expect(fakeDatabasesService.CreateDatabaseEvent(dbUUID, godo.DatabaseEvent{
+					ID:          "failover-event",
+					ServiceName: "events-are-cool",
+					EventType:   "cluster_node_failover",
+					CreateTime:  time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+				})).To(Succeed())
+			})
+
+			By("ensuring the event is emitted once on the DatabaseClusterReference", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, dbRefLookupKey, createdDBRef)).To(Succeed())
+					g.Expect(createdDBRef.Status.EventsCursor.LastEventIDs).To(ConsistOf("failover-event"))
+
+					var events eventsv1.EventList
+					g.Expect(k8sClient.List(ctx, &events, client.InNamespace("default"))).To(Succeed())
+					var emitted []eventsv1.Event
+					for _, event := range events.Items {
+						if event.Regarding.UID == createdDBRef.UID {
+							emitted = append(emitted, event)
+						}
+					}
+					g.Expect(emitted).To(HaveLen(1))
+					g.Expect(emitted[0].Reason).To(Equal("ClusterNodeFailover"))
+					g.Expect(emitted[0].Type).To(Equal(corev1.EventTypeWarning))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
 	})
 })
@@ -0,0 +1,480 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/do-operator/extgodo"
+	"github.com/digitalocean/godo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Keys read from a DatabaseLogsink's credentials Secret.
+const (
+	logsinkCredentialsCAKey     = "ca"
+	logsinkCredentialsCertKey   = "cert"
+	logsinkCredentialsKeyKey    = "key"
+	logsinkCredentialsURLKey    = "url"
+	logsinkCredentialsAPIKeyKey = "apiKey"
+)
+
+// DatabaseLogsinkReconciler reconciles a DatabaseLogsink object
+type DatabaseLogsinkReconciler struct {
+	client.Client
+	// APIReader reads from the API server rather than the cache, which only
+	// holds the Secrets generated by the operator. It's used to read
+	// credentials Secrets.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+	// Metadata is a cache from AddMetadataCache. The metadata of Secrets is
+	// watched through it to pick up changes to credentials.
+	Metadata cache.Cache
+}
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaselogsinks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaselogsinks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaselogsinks/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *DatabaseLogsinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ll := log.FromContext(ctx)
+	ll.Info("reconciling DatabaseLogsink", "name", req.Name)
+
+	var sink v1alpha1.DatabaseLogsink
+	err := r.Get(ctx, req.NamespacedName, &sink)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to get DatabaseLogsink %s: %s", req.NamespacedName, err)
+	}
+
+	originalSink := sink.DeepCopy()
+	inDeletion := !sink.DeletionTimestamp.IsZero()
+
+	defer func() {
+		var (
+			updated = false
+			errs    []error
+		)
+
+		if !cmp.Equal(sink.Finalizers, originalSink.Finalizers) {
+			ll.Info("updating DatabaseLogsink finalizers")
+			if err := r.Patch(ctx, sink.DeepCopy(), client.MergeFrom(originalSink)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update DatabaseLogsink: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if diff := cmp.Diff(sink.Status, originalSink.Status); diff != "" {
+			ll.WithValues("diff", diff).Info("status diff detected")
+
+			if err := r.Status().Patch(ctx, &sink, client.MergeFrom(originalSink)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update DatabaseLogsink status: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if len(errs) == 0 {
+			if updated {
+				ll.Info("DatabaseLogsink update succeeded")
+			} else {
+				ll.Info("no DatabaseLogsink update necessary")
+			}
+		}
+
+		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
+	}()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, sink.Namespace, sink.Spec.ProviderConfigRef)
+	if err != nil {
+		if inDeletion && releaseWithoutCredentials(ctx, r.Recorder, &sink, err) {
+			return result, nil
+		}
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	if inDeletion {
+		ll.Info("deleting DatabaseLogsink")
+		if sink.Status.SinkID == "" && !sink.Status.Creating {
+			// Logsink was never actually created; nothing to do.
+			controllerutil.RemoveFinalizer(&sink, finalizerName)
+			return ctrl.Result{}, nil
+		}
+		return r.reconcileDeletedLogsink(ctx, godoClient, &sink)
+	}
+
+	// If we haven't noted the cluster's UUID yet, look it up. Subsequent
+	// reconciles won't have to do this.
+	if sink.Status.ClusterUUID == "" {
+		cluster, err := getReferencedCluster(ctx, r.Client, sink.Namespace, sink.Spec.Cluster)
+		if err != nil {
+			return result, err
+		}
+
+		// Logsinks can't be created until the cluster is online. The
+		// logsink is reconciled again when the cluster's status changes.
+		if !cluster.isReady() {
+			ll.Info("database is still creating; waiting to create logsink")
+			return ctrl.Result{RequeueAfter: clusterNotReadyRequeueTime}, nil
+		}
+
+		sink.Status.ClusterUUID = cluster.UUID
+	}
+
+	ll.Info("reconciling DatabaseLogsink")
+	return r.reconcileLogsink(ctx, godoClient, &sink)
+}
+
+func (r *DatabaseLogsinkReconciler) reconcileLogsink(ctx context.Context, godoClient *godo.Client, sink *v1alpha1.DatabaseLogsink) (ctrl.Result, error) {
+	var (
+		clusterUUID = sink.Status.ClusterUUID
+		sinkType    = sink.Spec.SinkType()
+		ll          = log.FromContext(ctx).WithValues(
+			"cluster_uuid", clusterUUID,
+			"sink_name", sink.Spec.Name,
+		)
+	)
+
+	credentials, err := r.logsinkCredentials(ctx, sink)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	config, err := logsinkConfig(&sink.Spec, credentials)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	configHash, err := logsinkConfigHash(sinkType, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Make sure the logsink we know about still exists, so that we recreate
+	// it if it was deleted outside of the operator.
+	if sink.Status.SinkID != "" {
+		_, resp, err := godoClient.Databases.GetLogsink(ctx, clusterUUID, sink.Status.SinkID)
+		if err != nil {
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				return ctrl.Result{}, fmt.Errorf("getting logsink: %w", err)
+			}
+			ll.Info("logsink no longer exists; recreating it", "sink_id", sink.Status.SinkID)
+			sink.Status.SinkID = ""
+			sink.Status.ConfigHash = ""
+		}
+	}
+
+	controllerutil.AddFinalizer(sink, finalizerName)
+	sink.Status.Type = sinkType
+
+	if sink.Status.SinkID == "" {
+		existing, err := findLogsink(ctx, godoClient, clusterUUID, sink.Spec.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		switch {
+		case existing != nil && sink.Status.Creating:
+			// A previous reconcile created the logsink without managing to
+			// record its ID.
+			ll.Info("adopting logsink", "sink_id", existing.ID)
+			sink.Status.SinkID = existing.ID
+			sink.Status.Creating = false
+		case existing != nil:
+			r.Recorder.Eventf(sink, nil, corev1.EventTypeWarning, "LogsinkConflict", "CreateLogsink",
+				"A logsink named %q already exists on the cluster", sink.Spec.Name)
+			return ctrl.Result{}, fmt.Errorf("a logsink named %q already exists on the cluster and wasn't created by this DatabaseLogsink", sink.Spec.Name)
+		case !sink.Status.Creating:
+			// Record that we're creating the logsink before creating it, so
+			// that it's adopted if its ID isn't recorded.
+			sink.Status.Creating = true
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		default:
+			ll.Info("creating logsink")
+			created, err := r.createLogsink(ctx, godoClient, clusterUUID, sink.Spec.Name, sinkType, config)
+			if err != nil {
+				ll.Error(err, "unable to create logsink")
+				return ctrl.Result{}, fmt.Errorf("creating logsink: %w", err)
+			}
+			sink.Status.SinkID = created.ID
+			sink.Status.Creating = false
+			sink.Status.ConfigHash = configHash
+			return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+	}
+
+	// The API doesn't return credentials, so we can't compare the current
+	// configuration with the desired one. Instead, push the configuration
+	// whenever it or the credentials Secret changes.
+	if sink.Status.ConfigHash != configHash {
+		ll.Info("updating logsink", "sink_id", sink.Status.SinkID)
+		if err := r.updateLogsink(ctx, godoClient, clusterUUID, sink.Status.SinkID, config); err != nil {
+			ll.Error(err, "unable to update logsink")
+			return ctrl.Result{}, fmt.Errorf("updating logsink: %w", err)
+		}
+		sink.Status.ConfigHash = configHash
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// logsinkCredentials returns the contents of the logsink's credentials Secret,
+// or nil if it doesn't reference one.
+func (r *DatabaseLogsinkReconciler) logsinkCredentials(ctx context.Context, sink *v1alpha1.DatabaseLogsink) (map[string][]byte, error) {
+	ref := sink.Spec.CredentialsSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	var (
+		secret   corev1.Secret
+		secretNN = types.NamespacedName{
+			Namespace: sink.Namespace,
+			Name:      ref.Name,
+		}
+	)
+	if err := r.APIReader.Get(ctx, secretNN, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials Secret %s: %s", secretNN.Name, err)
+	}
+
+	return secret.Data, nil
+}
+
+// logsinkConfig builds the API configuration for a logsink from its spec and
+// credentials. Datadog logsinks aren't supported by godo, so their
+// configuration is returned as an *extgodo.DatabaseDatadogLogsinkConfig; all
+// other types are returned as a *godo.DatabaseLogsinkConfig.
+func logsinkConfig(spec *v1alpha1.DatabaseLogsinkSpec, credentials map[string][]byte) (any, error) {
+	switch {
+	case spec.Rsyslog != nil:
+		return &godo.DatabaseLogsinkConfig{
+			Server:  spec.Rsyslog.Server,
+			Port:    int(spec.Rsyslog.Port),
+			TLS:     spec.Rsyslog.TLS,
+			Format:  spec.Rsyslog.Format,
+			Logline: spec.Rsyslog.Logline,
+			SD:      spec.Rsyslog.SD,
+			CA:      string(credentials[logsinkCredentialsCAKey]),
+			Cert:    string(credentials[logsinkCredentialsCertKey]),
+			Key:     string(credentials[logsinkCredentialsKeyKey]),
+		}, nil
+	case spec.Elasticsearch != nil, spec.Opensearch != nil:
+		esConfig := spec.Elasticsearch
+		if esConfig == nil {
+			esConfig = spec.Opensearch
+		}
+		url := string(credentials[logsinkCredentialsURLKey])
+		if url == "" {
+			return nil, fmt.Errorf("credentials Secret has no key %q", logsinkCredentialsURLKey)
+		}
+		return &godo.DatabaseLogsinkConfig{
+			URL:          url,
+			IndexPrefix:  esConfig.IndexPrefix,
+			IndexDaysMax: int(esConfig.IndexDaysMax),
+			Timeout:      float32(esConfig.TimeoutSeconds),
+			CA:           string(credentials[logsinkCredentialsCAKey]),
+		}, nil
+	case spec.Datadog != nil:
+		apiKey := string(credentials[logsinkCredentialsAPIKeyKey])
+		if apiKey == "" {
+			return nil, fmt.Errorf("credentials Secret has no key %q", logsinkCredentialsAPIKeyKey)
+		}
+		return &extgodo.DatabaseDatadogLogsinkConfig{
+			Site:          spec.Datadog.Site,
+			DatadogAPIKey: apiKey,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no logsink configuration given")
+}
+
+// logsinkConfigHash returns a hash of a logsink's configuration.
+func logsinkConfigHash(sinkType string, config any) (string, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshaling logsink config: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(sinkType), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (r *DatabaseLogsinkReconciler) createLogsink(ctx context.Context, godoClient *godo.Client, clusterUUID, name, sinkType string, config any) (*godo.DatabaseLogsink, error) {
+	switch config := config.(type) {
+	case *godo.DatabaseLogsinkConfig:
+		created, _, err := godoClient.Databases.CreateLogsink(ctx, clusterUUID, &godo.DatabaseCreateLogsinkRequest{
+			Name:   name,
+			Type:   sinkType,
+			Config: config,
+		})
+		return created, err
+	case *extgodo.DatabaseDatadogLogsinkConfig:
+		req, err := godoClient.NewRequest(ctx, http.MethodPost, fmt.Sprintf("/v2/databases/%s/logsink", clusterUUID), &extgodo.DatabaseCreateDatadogLogsinkRequest{
+			Name:   name,
+			Type:   sinkType,
+			Config: config,
+		})
+		if err != nil {
+			return nil, err
+		}
+		root := struct {
+			Sink *godo.DatabaseLogsink `json:"sink"`
+		}{}
+		if _, err := godoClient.Do(ctx, req, &root); err != nil {
+			return nil, err
+		}
+		return root.Sink, nil
+	}
+
+	return nil, fmt.Errorf("unsupported logsink config %T", config)
+}
+
+func (r *DatabaseLogsinkReconciler) updateLogsink(ctx context.Context, godoClient *godo.Client, clusterUUID, sinkID string, config any) error {
+	switch config := config.(type) {
+	case *godo.DatabaseLogsinkConfig:
+		_, err := godoClient.Databases.UpdateLogsink(ctx, clusterUUID, sinkID, &godo.DatabaseUpdateLogsinkRequest{
+			Config: config,
+		})
+		return err
+	case *extgodo.DatabaseDatadogLogsinkConfig:
+		req, err := godoClient.NewRequest(ctx, http.MethodPut, fmt.Sprintf("/v2/databases/%s/logsink/%s", clusterUUID, sinkID), &extgodo.DatabaseUpdateDatadogLogsinkRequest{
+			Config: config,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = godoClient.Do(ctx, req, nil)
+		return err
+	}
+
+	return fmt.Errorf("unsupported logsink config %T", config)
+}
+
+func (r *DatabaseLogsinkReconciler) reconcileDeletedLogsink(ctx context.Context, godoClient *godo.Client, sink *v1alpha1.DatabaseLogsink) (ctrl.Result, error) {
+	ll := log.FromContext(ctx)
+	ll = ll.WithValues(
+		"cluster_uuid", sink.Status.ClusterUUID,
+		"sink_id", sink.Status.SinkID,
+	)
+
+	if sink.Status.SinkID == "" {
+		// The logsink may have been created without its ID being recorded.
+		existing, err := findLogsink(ctx, godoClient, sink.Status.ClusterUUID, sink.Spec.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if existing == nil {
+			controllerutil.RemoveFinalizer(sink, finalizerName)
+			return ctrl.Result{}, nil
+		}
+		sink.Status.SinkID = existing.ID
+	}
+
+	resp, err := godoClient.Databases.DeleteLogsink(ctx, sink.Status.ClusterUUID, sink.Status.SinkID)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		ll.Error(err, "unable to delete logsink")
+		return ctrl.Result{}, fmt.Errorf("deleting logsink: %w", err)
+	}
+	controllerutil.RemoveFinalizer(sink, finalizerName)
+
+	return ctrl.Result{}, nil
+}
+
+// findLogsink returns the cluster's logsink with the given name, or nil if it
+// has none.
+func findLogsink(ctx context.Context, godoClient *godo.Client, clusterUUID, name string) (*godo.DatabaseLogsink, error) {
+	sinks, _, err := godoClient.Databases.ListLogsinks(ctx, clusterUUID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing logsinks: %w", err)
+	}
+	for i := range sinks {
+		if sinks[i].Name == name {
+			return &sinks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// logsinksForSecret maps a Secret to the DatabaseLogsinks that read their
+// credentials from it, so that credentials are rotated as soon as the Secret
+// changes.
+func (r *DatabaseLogsinkReconciler) logsinksForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var sinks v1alpha1.DatabaseLogsinkList
+	if err := r.List(ctx, &sinks, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list DatabaseLogsinks for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for _, sink := range sinks.Items {
+		if ref := sink.Spec.CredentialsSecretRef; ref != nil && ref.Name == obj.GetName() {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&sink)})
+		}
+	}
+	return reqs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseLogsinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &v1alpha1.DatabaseLogsink{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &v1alpha1.DatabaseLogsinkList{}))
+
+	// Credentials Secrets aren't in the manager's cache, so watch the metadata
+	// of every Secret instead. The metadata changes along with the contents,
+	// so this is enough to notice rotated credentials.
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DatabaseLogsink{}).
+		Watches(&v1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&v1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
+		WatchesRawSource(source.Kind[client.Object](r.Metadata, secret, handler.EnqueueRequestsFromMapFunc(r.logsinksForSecret))).
+		Complete(r)
+}
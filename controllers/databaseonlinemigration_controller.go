@@ -0,0 +1,277 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/godo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// DatabaseOnlineMigrationReconciler reconciles a DatabaseOnlineMigration object
+type DatabaseOnlineMigrationReconciler struct {
+	client.Client
+	// APIReader reads from the API server rather than the cache, which only
+	// holds the Secrets generated by the operator. It's used to read source
+	// credentials Secrets.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseonlinemigrations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseonlinemigrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseonlinemigrations/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *DatabaseOnlineMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ll := log.FromContext(ctx)
+	ll.Info("reconciling DatabaseOnlineMigration", "name", req.Name)
+
+	var migration v1alpha1.DatabaseOnlineMigration
+	err := r.Get(ctx, req.NamespacedName, &migration)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to get DatabaseOnlineMigration %s: %s", req.NamespacedName, err)
+	}
+
+	originalMigration := migration.DeepCopy()
+	inDeletion := !migration.DeletionTimestamp.IsZero()
+
+	defer func() {
+		var (
+			updated = false
+			errs    []error
+		)
+
+		if !cmp.Equal(migration.Finalizers, originalMigration.Finalizers) {
+			ll.Info("updating DatabaseOnlineMigration finalizers")
+			if err := r.Patch(ctx, migration.DeepCopy(), client.MergeFrom(originalMigration)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update DatabaseOnlineMigration: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if diff := cmp.Diff(migration.Status, originalMigration.Status); diff != "" {
+			ll.WithValues("diff", diff).Info("status diff detected")
+
+			if err := r.Status().Patch(ctx, &migration, client.MergeFrom(originalMigration)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update DatabaseOnlineMigration status: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if len(errs) == 0 {
+			if updated {
+				ll.Info("DatabaseOnlineMigration update succeeded")
+			} else {
+				ll.Info("no DatabaseOnlineMigration update necessary")
+			}
+		}
+
+		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
+	}()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, migration.Namespace, migration.Spec.ProviderConfigRef)
+	if err != nil {
+		if inDeletion && releaseWithoutCredentials(ctx, r.Recorder, &migration, err) {
+			return result, nil
+		}
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	if inDeletion {
+		ll.Info("deleting DatabaseOnlineMigration")
+		if migration.Status.MigrationID == "" {
+			// Migration was never actually started; nothing to do.
+			controllerutil.RemoveFinalizer(&migration, finalizerName)
+			return ctrl.Result{}, nil
+		}
+		return r.reconcileDeletedMigration(ctx, godoClient, &migration)
+	}
+
+	// If we haven't noted the cluster's UUID yet, look it up. Subsequent
+	// reconciles won't have to do this.
+	if migration.Status.ClusterUUID == "" {
+		cluster, err := getReferencedCluster(ctx, r.Client, migration.Namespace, migration.Spec.Cluster)
+		if err != nil {
+			return result, err
+		}
+
+		// A migration can't be started until the cluster is online. Watching
+		// the cluster reconciles the migration again once it is.
+		if !cluster.isReady() {
+			ll.Info("database is still creating; waiting to start migration")
+			return ctrl.Result{RequeueAfter: clusterNotReadyRequeueTime}, nil
+		}
+
+		migration.Status.ClusterUUID = cluster.UUID
+	}
+
+	ll.Info("reconciling DatabaseOnlineMigration")
+	return r.reconcileMigration(ctx, godoClient, &migration)
+}
+
+func (r *DatabaseOnlineMigrationReconciler) reconcileMigration(ctx context.Context, godoClient *godo.Client, migration *v1alpha1.DatabaseOnlineMigration) (ctrl.Result, error) {
+	var (
+		clusterUUID = migration.Status.ClusterUUID
+		ll          = log.FromContext(ctx).WithValues(
+			"cluster_uuid", clusterUUID,
+			"source_host", migration.Spec.Source.Host,
+		)
+	)
+
+	current, resp, err := godoClient.Databases.GetOnlineMigrationStatus(ctx, clusterUUID)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return ctrl.Result{}, fmt.Errorf("getting online migration status: %w", err)
+	}
+	if err != nil {
+		current = nil
+	}
+
+	if migration.Status.MigrationID == "" {
+		// A previous reconcile may have started the migration without managing
+		// to record its ID. Only one migration can run at a time, so adopt any
+		// migration that's still in progress rather than starting another.
+		if current != nil && !v1alpha1.IsTerminalOnlineMigrationStatus(current.Status) {
+			ll.Info("adopting in-progress online migration", "migration_id", current.ID)
+		} else {
+			ll.Info("starting online migration")
+			current, err = r.startMigration(ctx, godoClient, migration)
+			if err != nil {
+				ll.Error(err, "unable to start online migration")
+				return ctrl.Result{}, err
+			}
+		}
+		controllerutil.AddFinalizer(migration, finalizerName)
+		migration.Status.MigrationID = current.ID
+	}
+
+	// The API only reports the most recent migration, so if another one has
+	// been started since ours there is nothing left for us to mirror.
+	if current == nil || current.ID != migration.Status.MigrationID {
+		ll.Info("online migration has been superseded", "migration_id", migration.Status.MigrationID)
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+
+	migration.Status.Status = current.Status
+	migration.Status.CreatedAt = current.CreatedAt
+
+	if v1alpha1.IsTerminalOnlineMigrationStatus(migration.Status.Status) {
+		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	}
+	// Check on in-progress migrations more often so the status stays fresh.
+	return ctrl.Result{RequeueAfter: time.Minute}, nil
+}
+
+func (r *DatabaseOnlineMigrationReconciler) startMigration(ctx context.Context, godoClient *godo.Client, migration *v1alpha1.DatabaseOnlineMigration) (*godo.DatabaseOnlineMigrationStatus, error) {
+	var (
+		source   = migration.Spec.Source
+		secret   corev1.Secret
+		secretNN = types.NamespacedName{
+			Namespace: migration.Namespace,
+			Name:      source.CredentialsSecretRef.Name,
+		}
+	)
+	if err := r.APIReader.Get(ctx, secretNN, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get source credentials Secret %s: %s", secretNN.Name, err)
+	}
+
+	started, _, err := godoClient.Databases.StartOnlineMigration(ctx, migration.Status.ClusterUUID, &godo.DatabaseStartOnlineMigrationRequest{
+		Source: &godo.DatabaseOnlineMigrationConfig{
+			Host:         source.Host,
+			Port:         int(source.Port),
+			DatabaseName: source.DatabaseName,
+			Username:     string(secret.Data["username"]),
+			Password:     string(secret.Data["password"]),
+		},
+		DisableSSL: migration.Spec.DisableSSL,
+		IgnoreDBs:  migration.Spec.IgnoreDBs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("starting online migration: %w", err)
+	}
+
+	return started, nil
+}
+
+func (r *DatabaseOnlineMigrationReconciler) reconcileDeletedMigration(ctx context.Context, godoClient *godo.Client, migration *v1alpha1.DatabaseOnlineMigration) (ctrl.Result, error) {
+	ll := log.FromContext(ctx)
+	ll = ll.WithValues(
+		"cluster_uuid", migration.Status.ClusterUUID,
+		"migration_id", migration.Status.MigrationID,
+	)
+
+	current, resp, err := godoClient.Databases.GetOnlineMigrationStatus(ctx, migration.Status.ClusterUUID)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return ctrl.Result{}, fmt.Errorf("getting online migration status: %w", err)
+	}
+
+	// Only stop the migration if it's still ours and still running.
+	if err == nil && current.ID == migration.Status.MigrationID && !v1alpha1.IsTerminalOnlineMigrationStatus(current.Status) {
+		ll.Info("stopping online migration")
+		resp, err := godoClient.Databases.StopOnlineMigration(ctx, migration.Status.ClusterUUID, migration.Status.MigrationID)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			ll.Error(err, "unable to stop online migration")
+			return ctrl.Result{}, fmt.Errorf("stopping online migration: %w", err)
+		}
+	}
+	controllerutil.RemoveFinalizer(migration, finalizerName)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseOnlineMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &v1alpha1.DatabaseOnlineMigration{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &v1alpha1.DatabaseOnlineMigrationList{}))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DatabaseOnlineMigration{}).
+		Watches(&v1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&v1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
+		Complete(r)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("DatabaseOnlineMigration controller", func() {
+	Context("When reconciling a DatabaseOnlineMigration", func() {
+		It("should start and stop an online migration", func() {
+			var (
+				dbCluster          = mustCreateDatabaseClusterWithEngine("pg")
+				migrationLookupKey = types.NamespacedName{
+					Name:      "self-hosted-pg",
+					Namespace: "default",
+				}
+				createdMigration = &v1alpha1.DatabaseOnlineMigration{}
+			)
+
+			By("creating the source credentials Secret", func() {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "self-hosted-pg-credentials",
+						Namespace: "default",
+					},
+					StringData: map[string]string{
+						"username": "postgres",
+						"password": "password",
+					},
+				}
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			})
+
+			By("creating a DatabaseOnlineMigration object", func() {
+				migration := &v1alpha1.DatabaseOnlineMigration{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.DatabaseOnlineMigrationKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      migrationLookupKey.Name,
+						Namespace: migrationLookupKey.Namespace,
+					},
+					Spec: v1alpha1.DatabaseOnlineMigrationSpec{
+						Cluster: corev1.TypedLocalObjectReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						Source: v1alpha1.OnlineMigrationSource{
+							Host:         "pg.example.com",
+							Port:         5432,
+							DatabaseName: "app",
+							CredentialsSecretRef: corev1.LocalObjectReference{
+								Name: "self-hosted-pg-credentials",
+							},
+						},
+						IgnoreDBs: []string{"scratch"},
+					},
+				}
+				Expect(k8sClient.Create(ctx, migration)).To(Succeed())
+			})
+
+			By("ensuring the DatabaseOnlineMigration status mirrors the migration", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, migrationLookupKey, createdMigration)).To(Succeed())
+					g.Expect(createdMigration.Status.ClusterUUID).To(Equal(dbCluster.Status.UUID))
+					g.Expect(createdMigration.Status.MigrationID).NotTo(BeEmpty())
+					g.Expect(createdMigration.Status.Status).To(Equal("syncing"))
+					g.Expect(createdMigration.Status.CreatedAt).NotTo(BeEmpty())
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("deleting the DatabaseOnlineMigration object", func() {
+				Expect(k8sClient.Delete(ctx, createdMigration)).To(Succeed())
+				// Wait for the object to go away.
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, migrationLookupKey, createdMigration)
+					return kerrors.IsNotFound(err)
+				}, timeout, interval).Should(BeTrue())
+			})
+
+			By("ensuring the migration is stopped in godo", func() {
+				Eventually(func(g Gomega) {
+					migration, _, err := fakeDatabasesService.GetOnlineMigrationStatus(ctx, dbCluster.Status.UUID)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(migration.ID).To(Equal(createdMigration.Status.MigrationID))
+					g.Expect(migration.Status).To(Equal(v1alpha1.OnlineMigrationStatusCanceled))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
+	})
+})
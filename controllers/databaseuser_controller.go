@@ -18,21 +18,30 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/godo"
 	"github.com/google/go-cmp/cmp"
 )
@@ -40,14 +49,23 @@ import (
 // DatabaseUserReconciler reconciles a DatabaseUser object
 type DatabaseUserReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	GodoClient *godo.Client
+	// APIReader reads from the API server rather than the cache. It's used
+	// to confirm that a credentials Secret is really gone before resetting a
+	// password that can't be recovered, and to find generated objects that
+	// predate the managed-by label.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+
+	connections clusterConnections
 }
 
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseusers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseusers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseusers/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -102,6 +120,17 @@ func (r *DatabaseUserReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
 	}()
+	defer func() { setReconcileFailedCondition(&user.Status.Conditions, user.Generation, retErr) }()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, user.Namespace, user.Spec.ProviderConfigRef)
+	if err != nil {
+		if inDeletion && releaseWithoutCredentials(ctx, r.Recorder, &user, err) {
+			return result, nil
+		}
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
 
 	if inDeletion {
 		ll.Info("deleting DatabaseUser")
@@ -110,14 +139,21 @@ func (r *DatabaseUserReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			controllerutil.RemoveFinalizer(&user, finalizerName)
 			return ctrl.Result{}, nil
 		}
-		return r.reconcileDeletedDBUser(ctx, user.Status.ClusterUUID, &user)
+		return r.reconcileDeletedDBUser(ctx, godoClient, user.Status.ClusterUUID, &user)
+	}
+
+	// The reference is checked on every reconcile so that revoking a grant
+	// stops the operator from managing objects in the cluster.
+	if err := checkClusterGrant(ctx, r.Client, user.Namespace, user.Spec.Cluster); err != nil {
+		ll.Error(err, "cluster reference not allowed")
+		return result, err
 	}
 
 	var (
 		clusterUUID   = user.Status.ClusterUUID
 		clusterStatus string
 		clusterNN     = types.NamespacedName{
-			Namespace: user.Namespace,
+			Namespace: user.Spec.Cluster.NamespaceFor(user.Namespace),
 			Name:      user.Spec.Cluster.Name,
 		}
 	)
@@ -145,11 +181,12 @@ func (r *DatabaseUserReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return result, fmt.Errorf("unexpected Kind for Cluster: %s", user.Spec.Cluster.Kind)
 		}
 
-		// User creation will fail if the cluster is still being created. Schedule a
-		// quick retry in those cases so we don't exponentially back off.
+		// User creation will fail if the cluster is still being created. The
+		// user is reconciled again when the cluster's status changes.
 		if clusterStatus == "" || clusterStatus == "creating" {
 			ll.Info("database is still creating; waiting to create user")
-			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+			setReadyCondition(&user.Status.Conditions, user.Generation, metav1.ConditionFalse, readyReasonClusterNotReady, "Waiting for the cluster to be created.")
+			return ctrl.Result{RequeueAfter: clusterNotReadyRequeueTime}, nil
 		}
 
 		user.Status.ClusterUUID = clusterUUID
@@ -157,10 +194,10 @@ func (r *DatabaseUserReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	user.Status.ClusterUUID = clusterUUID
 	ll.Info("reconciling DatabaseUser")
-	return r.reconcileDBUser(ctx, clusterUUID, &user)
+	return r.reconcileDBUser(ctx, godoClient, clusterUUID, &user)
 }
 
-func (r *DatabaseUserReconciler) reconcileDBUser(ctx context.Context, clusterUUID string, user *v1alpha1.DatabaseUser) (ctrl.Result, error) {
+func (r *DatabaseUserReconciler) reconcileDBUser(ctx context.Context, godoClient *godo.Client, clusterUUID string, user *v1alpha1.DatabaseUser) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 	ll = ll.WithValues(
 		"cluster_uuid", clusterUUID,
@@ -174,63 +211,356 @@ func (r *DatabaseUserReconciler) reconcileDBUser(ctx context.Context, clusterUUI
 	// have an ID other than the username we don't have a way to distinguish for
 	// sure.
 
-	dbUser, resp, err := r.GodoClient.Databases.GetUser(ctx, clusterUUID, user.Spec.Username)
-	if err != nil && resp.StatusCode != http.StatusNotFound {
-		return ctrl.Result{}, fmt.Errorf("checking for existing DB user: %v", err)
+	dbUser, resp, err := godoClient.Databases.GetUser(ctx, clusterUUID, user.Spec.Username)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return ctrl.Result{}, fmt.Errorf("checking for existing DB user: %w", err)
 	}
 
-	if resp.StatusCode == http.StatusNotFound {
+	if err != nil {
 		createReq := &godo.DatabaseCreateUserRequest{
-			Name: user.Spec.Username,
+			Name:     user.Spec.Username,
+			Settings: userSettingsForSpec(&user.Spec),
+		}
+		if user.Spec.MySQLAuthPlugin != "" {
+			createReq.MySQLSettings = &godo.DatabaseMySQLUserSettings{
+				AuthPlugin: user.Spec.MySQLAuthPlugin,
+			}
 		}
-		dbUser, _, err = r.GodoClient.Databases.CreateUser(ctx, clusterUUID, createReq)
+		dbUser, _, err = godoClient.Databases.CreateUser(ctx, clusterUUID, createReq)
 		if err != nil {
 			ll.Error(err, "unable to create user")
-			return ctrl.Result{}, fmt.Errorf("creating DB user: %v", err)
+			return ctrl.Result{}, fmt.Errorf("creating DB user: %w", err)
 		}
+		// A new user already has a fresh password, so a rotation requested
+		// before it was created is handled.
+		user.Status.LastRotationRequest = user.Annotations[v1alpha1.RotatePasswordAnnotation]
+	} else {
+		var (
+			now           = metav1.Now()
+			plugin        = user.Spec.MySQLAuthPlugin
+			pluginChanged = plugin != "" && (dbUser.MySQLSettings == nil || dbUser.MySQLSettings.AuthPlugin != plugin)
+			rotationDue   = passwordRotationDue(user, now.Time)
+			passwordLost  bool
+		)
+		if dbUser.Password == "" && user.Spec.ResetPasswordWhenLost {
+			passwordLost, err = r.credentialsLost(ctx, user)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if pluginChanged || rotationDue || passwordLost {
+			// The auth plugin can only be changed by resetting the user's
+			// auth, which also generates a new password.
+			ll.Info("resetting user auth", "plugin_changed", pluginChanged, "rotation_due", rotationDue, "password_lost", passwordLost)
+			resetReq := &godo.DatabaseResetUserAuthRequest{}
+			if pluginChanged {
+				resetReq.MySQLSettings = &godo.DatabaseMySQLUserSettings{AuthPlugin: plugin}
+			} else if dbUser.MySQLSettings != nil {
+				resetReq.MySQLSettings = dbUser.MySQLSettings
+			}
+			dbUser, _, err = godoClient.Databases.ResetUserAuth(ctx, clusterUUID, user.Spec.Username, resetReq)
+			if err != nil {
+				ll.Error(err, "unable to reset user auth")
+				return ctrl.Result{}, fmt.Errorf("resetting DB user auth: %w", err)
+			}
+			user.Status.LastRotatedAt = &now
+			user.Status.LastRotationRequest = user.Annotations[v1alpha1.RotatePasswordAnnotation]
+			if passwordLost {
+				user.Status.LostPasswordResetAt = &now
+			}
+		}
+	}
+
+	if !userSettingsInSync(&user.Spec, dbUser.Settings) {
+		ll.Info("updating user settings")
+		settings := userSettingsForSpec(&user.Spec)
+		if settings == nil {
+			settings = &godo.DatabaseUserSettings{}
+		}
+		updatedUser, _, err := godoClient.Databases.UpdateUser(ctx, clusterUUID, user.Spec.Username, &godo.DatabaseUpdateUserRequest{
+			Settings: settings,
+		})
+		if err != nil {
+			ll.Error(err, "unable to update user settings")
+			return ctrl.Result{}, fmt.Errorf("updating DB user settings: %w", err)
+		}
+		dbUser.Settings = updatedUser.Settings
 	}
 
 	controllerutil.AddFinalizer(user, finalizerName)
 	user.Status.Role = dbUser.Role
 
-	err = r.ensureOwnedObjects(ctx, user, dbUser)
+	previousHash := user.Status.CredentialsHash
+	err = r.ensureOwnedObjects(ctx, godoClient, user, dbUser)
 	if err != nil {
 		ll.Error(err, "unable to ensure user-related objects")
-		return ctrl.Result{}, fmt.Errorf("ensuring user-related objects: %v", err)
+		return ctrl.Result{}, fmt.Errorf("ensuring user-related objects: %w", err)
+	}
+
+	err = r.rollOutTargets(ctx, user, previousHash)
+	if err != nil {
+		// Keep the previous hash so the roll out is retried.
+		user.Status.CredentialsHash = previousHash
+		ll.Error(err, "unable to roll out targets")
+		return ctrl.Result{}, fmt.Errorf("rolling out targets: %w", err)
 	}
+	setReadyCondition(&user.Status.Conditions, user.Generation, metav1.ConditionTrue, readyReasonReconciled, "The user is up to date.")
 
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-func (r *DatabaseUserReconciler) ensureOwnedObjects(ctx context.Context, user *v1alpha1.DatabaseUser, dbUser *godo.DatabaseUser) error {
+// passwordRotationDue returns whether the user's password should be rotated,
+// either because a rotation was requested or the rotation interval has passed
+// since the last rotation or the user's creation.
+func passwordRotationDue(user *v1alpha1.DatabaseUser, now time.Time) bool {
+	if request := user.Annotations[v1alpha1.RotatePasswordAnnotation]; request != "" && request != user.Status.LastRotationRequest {
+		return true
+	}
+
+	rotation := user.Spec.PasswordRotation
+	if rotation == nil || rotation.Interval == nil {
+		return false
+	}
+	lastRotated := user.CreationTimestamp
+	if user.Status.LastRotatedAt != nil {
+		lastRotated = *user.Status.LastRotatedAt
+	}
+	return !now.Before(lastRotated.Add(rotation.Interval.Duration))
+}
+
+// credentialsLost returns whether the user's credentials Secret has been
+// deleted or no longer holds a password. The Secret is read from the API
+// server, since the cache may not have seen a Secret we just created.
+func (r *DatabaseUserReconciler) credentialsLost(ctx context.Context, user *v1alpha1.DatabaseUser) (bool, error) {
+	secretNN := types.NamespacedName{
+		Namespace: user.Namespace,
+		Name:      credentialsSecretName(user),
+	}
+	var secret corev1.Secret
+	if err := r.APIReader.Get(ctx, secretNN, &secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("getting credentials Secret %s: %w", secretNN.Name, err)
+	}
+	return len(secret.Data["password"]) == 0, nil
+}
+
+// credentialsSecretName returns the name of the user's credentials Secret.
+func credentialsSecretName(user *v1alpha1.DatabaseUser) string {
+	var configured string
+	if user.Spec.Output != nil {
+		configured = user.Spec.Output.CredentialsSecretName
+	}
+	return outputName(configured, user, credentialsSuffix)
+}
+
+// rollOutTargets sets the credentials hash on the pod templates of the user's
+// rollout targets when the credentials have changed from previousHash, so that
+// they roll out.
+func (r *DatabaseUserReconciler) rollOutTargets(ctx context.Context, user *v1alpha1.DatabaseUser, previousHash string) error {
+	if user.Spec.PasswordRotation == nil || user.Status.CredentialsHash == previousHash {
+		return nil
+	}
+	// The first credentials don't replace any that the targets could be
+	// using, so there's nothing to roll out.
+	if previousHash == "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{
+						v1alpha1.CredentialsHashAnnotation: user.Status.CredentialsHash,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling patch: %w", err)
+	}
+
+	for _, target := range user.Spec.PasswordRotation.RolloutTargets {
+		var obj client.Object
+		switch target.Kind {
+		case "Deployment":
+			obj = &appsv1.Deployment{}
+		case "StatefulSet":
+			obj = &appsv1.StatefulSet{}
+		default:
+			// The CRD schema should ensure we never get here.
+			return fmt.Errorf("unexpected rollout target kind: %s", target.Kind)
+		}
+		obj.SetNamespace(user.Namespace)
+		obj.SetName(target.Name)
+
+		if err := r.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			if kerrors.IsNotFound(err) {
+				log.FromContext(ctx).Info("rollout target not found", "kind", target.Kind, "name", target.Name)
+				continue
+			}
+			return fmt.Errorf("patching %s %s: %w", target.Kind, target.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// userSettingsForSpec returns the engine-specific settings for a user, or nil
+// if the user has none.
+func userSettingsForSpec(spec *v1alpha1.DatabaseUserSpec) *godo.DatabaseUserSettings {
+	if len(spec.ACL) == 0 && spec.OpenSearch == nil && spec.MongoDB == nil {
+		return nil
+	}
+
+	settings := &godo.DatabaseUserSettings{}
+	for _, acl := range spec.ACL {
+		settings.ACL = append(settings.ACL, &godo.KafkaACL{
+			Topic:      acl.Topic,
+			Permission: acl.Permission,
+		})
+	}
+	if spec.OpenSearch != nil {
+		for _, acl := range spec.OpenSearch.ACL {
+			settings.OpenSearchACL = append(settings.OpenSearchACL, &godo.OpenSearchACL{
+				Index:      acl.Index,
+				Permission: acl.Permission,
+			})
+		}
+	}
+	if spec.MongoDB != nil {
+		settings.MongoUserSettings = &godo.MongoUserSettings{
+			Databases: slices.Clone(spec.MongoDB.Databases),
+			Role:      spec.MongoDB.Role,
+		}
+	}
+	return settings
+}
+
+// userSettingsInSync returns whether a user's settings in the API match its
+// spec. Only the settings set in the spec are compared, so that we don't fight
+// the defaults the API applies to the others. Lists are compared ignoring
+// their order and ACL IDs.
+func userSettingsInSync(spec *v1alpha1.DatabaseUserSpec, settings *godo.DatabaseUserSettings) bool {
+	desired := normalizeUserSettings(userSettingsForSpec(spec))
+	actual := normalizeUserSettings(settings)
+	if spec.OpenSearch == nil {
+		actual.OpenSearchACL = nil
+	}
+	if spec.MongoDB == nil {
+		actual.MongoUserSettings = nil
+	}
+	return cmp.Equal(desired, actual)
+}
+
+// normalizeUserSettings returns a copy of settings with ACL IDs removed and
+// all lists sorted, so that settings can be compared.
+func normalizeUserSettings(settings *godo.DatabaseUserSettings) godo.DatabaseUserSettings {
+	var normalized godo.DatabaseUserSettings
+	if settings == nil {
+		return normalized
+	}
+
+	for _, acl := range settings.ACL {
+		normalized.ACL = append(normalized.ACL, &godo.KafkaACL{
+			Topic:      acl.Topic,
+			Permission: acl.Permission,
+		})
+	}
+	slices.SortFunc(normalized.ACL, func(a, b *godo.KafkaACL) int {
+		if c := strings.Compare(a.Topic, b.Topic); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Permission, b.Permission)
+	})
+
+	for _, acl := range settings.OpenSearchACL {
+		normalized.OpenSearchACL = append(normalized.OpenSearchACL, &godo.OpenSearchACL{
+			Index:      acl.Index,
+			Permission: acl.Permission,
+		})
+	}
+	slices.SortFunc(normalized.OpenSearchACL, func(a, b *godo.OpenSearchACL) int {
+		if c := strings.Compare(a.Index, b.Index); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Permission, b.Permission)
+	})
+
+	if mongo := settings.MongoUserSettings; mongo != nil {
+		normalized.MongoUserSettings = &godo.MongoUserSettings{
+			Role: mongo.Role,
+		}
+		if len(mongo.Databases) > 0 {
+			normalized.MongoUserSettings.Databases = slices.Sorted(slices.Values(mongo.Databases))
+		}
+	}
+
+	return normalized
+}
+
+func (r *DatabaseUserReconciler) ensureOwnedObjects(ctx context.Context, godoClient *godo.Client, user *v1alpha1.DatabaseUser, dbUser *godo.DatabaseUser) error {
 	// For some database engines the password is not returned when fetching a
 	// user, only on initial creation. Avoid creating or updating the user
 	// credentials secret if the password is empty, so we don't clear the
-	// password after creation.
+	// password after creation. Set ResetPasswordWhenLost to recreate a lost
+	// Secret with a new password.
 	if dbUser.Password == "" {
 		return nil
 	}
 
-	obj := credentialsSecretForDBUser(user, dbUser)
+	cluster, err := getClusterReference(ctx, r.Client, user.Namespace, user.Spec.Cluster)
+	if err != nil {
+		return err
+	}
+	db, err := r.connections.get(ctx, godoClient, user.Status.ClusterUUID, cluster.ConnectionHash)
+	if err != nil {
+		return err
+	}
+
+	output := user.Spec.Output
+	if output == nil {
+		output = &v1alpha1.UserOutput{}
+	}
+
+	obj := credentialsSecretForDBUser(credentialsSecretName(user), user, db, dbUser, user.Spec.Database)
+	if err := applySecretTemplate(ctx, godoClient, obj, user.Spec.SecretTemplate, db, dbUser.Name, dbUser.Password, user.Spec.Database); err != nil {
+		return err
+	}
+	setOutputMetadata(obj, &output.OutputMetadata)
+	if err := deleteSecretOfOtherType(ctx, r.Client, r.APIReader, user, obj); err != nil {
+		return err
+	}
+	// Hash before applying, since the response doesn't include StringData.
+	credentialsHash := secretDataHash(obj)
 	controllerutil.SetControllerReference(user, obj, r.Scheme)
 	if err := r.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner("do-operator")); err != nil {
 		return fmt.Errorf("applying object %s: %s", client.ObjectKeyFromObject(obj), err)
 	}
+	user.Status.CredentialsHash = credentialsHash
+
+	if err := newGeneratedOutput(obj, &user.Status.Outputs.CredentialsSecretName, user, credentialsSuffix).record(ctx, r.Client, r.APIReader, user); err != nil {
+		return err
+	}
+	user.Status.Binding = serviceBindingForOutputs(user.Status.Outputs)
 
 	return nil
 }
 
-func (r *DatabaseUserReconciler) reconcileDeletedDBUser(ctx context.Context, clusterUUID string, user *v1alpha1.DatabaseUser) (ctrl.Result, error) {
+func (r *DatabaseUserReconciler) reconcileDeletedDBUser(ctx context.Context, godoClient *godo.Client, clusterUUID string, user *v1alpha1.DatabaseUser) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 	ll = ll.WithValues(
 		"cluster_uuid", clusterUUID,
 		"user_name", user.Spec.Username,
 	)
 
-	_, err := r.GodoClient.Databases.DeleteUser(ctx, clusterUUID, user.Spec.Username)
+	_, err := godoClient.Databases.DeleteUser(ctx, clusterUUID, user.Spec.Username)
 	if err != nil {
 		ll.Error(err, "unable to delete user")
-		return ctrl.Result{}, fmt.Errorf("deleting user: %v", err)
+		return ctrl.Result{}, fmt.Errorf("deleting user: %w", err)
 	}
 	controllerutil.RemoveFinalizer(user, finalizerName)
 
@@ -239,7 +569,15 @@ func (r *DatabaseUserReconciler) reconcileDeletedDBUser(ctx context.Context, clu
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DatabaseUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &databasesv1alpha1.DatabaseUser{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &databasesv1alpha1.DatabaseUserList{}))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databasesv1alpha1.DatabaseUser{}).
+		Owns(&corev1.Secret{}).
+		Watches(&databasesv1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&databasesv1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
 		Complete(r)
 }
@@ -21,17 +21,21 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerror "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/digitalocean/do-operator/api/v1alpha1"
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/godo"
 	"github.com/google/go-cmp/cmp"
 )
@@ -39,14 +43,20 @@ import (
 // DatabaseUserReferenceReconciler reconciles a DatabaseUserReference object
 type DatabaseUserReferenceReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	GodoClient *godo.Client
+	// APIReader reads from the API server rather than the cache. It's used
+	// to find generated objects that predate the managed-by label, which
+	// aren't cached until they're next applied.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+
+	connections clusterConnections
 }
 
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseuserreferences,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseuserreferences/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=databases.digitalocean.com,resources=databaseuserreferences/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -91,11 +101,25 @@ func (r *DatabaseUserReferenceReconciler) Reconcile(ctx context.Context, req ctr
 
 		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
 	}()
+	defer func() { setReconcileFailedCondition(&userRef.Status.Conditions, userRef.Generation, retErr) }()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, userRef.Namespace, userRef.Spec.ProviderConfigRef)
+	if err != nil {
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	// Once a grant is revoked, the credentials Secret is no longer updated.
+	if err := checkClusterGrant(ctx, r.Client, userRef.Namespace, userRef.Spec.Cluster); err != nil {
+		ll.Error(err, "cluster reference not allowed")
+		return result, err
+	}
 
 	var (
 		clusterUUID = userRef.Status.ClusterUUID
 		clusterNN   = types.NamespacedName{
-			Namespace: userRef.Namespace,
+			Namespace: userRef.Spec.Cluster.NamespaceFor(userRef.Namespace),
 			Name:      userRef.Spec.Cluster.Name,
 		}
 	)
@@ -125,10 +149,10 @@ func (r *DatabaseUserReferenceReconciler) Reconcile(ctx context.Context, req ctr
 	}
 
 	ll.Info("reconciling DatabaseUserReference")
-	return r.reconcileDBUserReference(ctx, clusterUUID, &userRef)
+	return r.reconcileDBUserReference(ctx, godoClient, clusterUUID, &userRef)
 }
 
-func (r *DatabaseUserReferenceReconciler) reconcileDBUserReference(ctx context.Context, clusterUUID string, userRef *v1alpha1.DatabaseUserReference) (ctrl.Result, error) {
+func (r *DatabaseUserReferenceReconciler) reconcileDBUserReference(ctx context.Context, godoClient *godo.Client, clusterUUID string, userRef *v1alpha1.DatabaseUserReference) (ctrl.Result, error) {
 	ll := log.FromContext(ctx)
 	ll = ll.WithValues(
 		"cluster_uuid", clusterUUID,
@@ -138,35 +162,67 @@ func (r *DatabaseUserReferenceReconciler) reconcileDBUserReference(ctx context.C
 	// The validating webhook checks that the user exists, so normally this
 	// should work. However, the user could have been deleted in which case
 	// we'll fail and back off in case it gets re-created.
-	dbUser, _, err := r.GodoClient.Databases.GetUser(ctx, clusterUUID, userRef.Spec.Username)
+	dbUser, _, err := godoClient.Databases.GetUser(ctx, clusterUUID, userRef.Spec.Username)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("looking up DB user: %v", err)
+		return ctrl.Result{}, fmt.Errorf("looking up DB user: %w", err)
 	}
 
 	userRef.Status.Role = dbUser.Role
 
-	err = r.ensureOwnedObjects(ctx, userRef, dbUser)
+	err = r.ensureOwnedObjects(ctx, godoClient, userRef, dbUser)
 	if err != nil {
 		ll.Error(err, "unable to ensure user-related objects")
-		return ctrl.Result{}, fmt.Errorf("ensuring user-related objects: %v", err)
+		return ctrl.Result{}, fmt.Errorf("ensuring user-related objects: %w", err)
 	}
+	setReadyCondition(&userRef.Status.Conditions, userRef.Generation, metav1.ConditionTrue, readyReasonReconciled, "The user reference is up to date.")
 
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-func (r *DatabaseUserReferenceReconciler) ensureOwnedObjects(ctx context.Context, userRef *v1alpha1.DatabaseUserReference, dbUser *godo.DatabaseUser) error {
-	obj := credentialsSecretForDBUser(userRef, dbUser)
+func (r *DatabaseUserReferenceReconciler) ensureOwnedObjects(ctx context.Context, godoClient *godo.Client, userRef *v1alpha1.DatabaseUserReference, dbUser *godo.DatabaseUser) error {
+	cluster, err := getClusterReference(ctx, r.Client, userRef.Namespace, userRef.Spec.Cluster)
+	if err != nil {
+		return err
+	}
+	db, err := r.connections.get(ctx, godoClient, userRef.Status.ClusterUUID, cluster.ConnectionHash)
+	if err != nil {
+		return err
+	}
+
+	output := userRef.Spec.Output
+	if output == nil {
+		output = &v1alpha1.UserOutput{}
+	}
+
+	obj := credentialsSecretForDBUser(outputName(output.CredentialsSecretName, userRef, credentialsSuffix), userRef, db, dbUser, userRef.Spec.Database)
+	setOutputMetadata(obj, &output.OutputMetadata)
+	if err := deleteSecretOfOtherType(ctx, r.Client, r.APIReader, userRef, obj); err != nil {
+		return err
+	}
 	controllerutil.SetControllerReference(userRef, obj, r.Scheme)
 	if err := r.Patch(ctx, obj, client.Apply, client.ForceOwnership, client.FieldOwner("do-operator")); err != nil {
 		return fmt.Errorf("applying object %s: %s", client.ObjectKeyFromObject(obj), err)
 	}
 
+	if err := newGeneratedOutput(obj, &userRef.Status.Outputs.CredentialsSecretName, userRef, credentialsSuffix).record(ctx, r.Client, r.APIReader, userRef); err != nil {
+		return err
+	}
+	userRef.Status.Binding = serviceBindingForOutputs(userRef.Status.Outputs)
+
 	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DatabaseUserReferenceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &databasesv1alpha1.DatabaseUserReference{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &databasesv1alpha1.DatabaseUserReferenceList{}))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databasesv1alpha1.DatabaseUserReference{}).
+		Owns(&corev1.Secret{}).
+		Watches(&databasesv1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&databasesv1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
 		Complete(r)
 }
@@ -6,9 +6,11 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("DatabaseUserReference controller", func() {
@@ -40,12 +42,13 @@ var _ = Describe("DatabaseUserReference controller", func() {
 						Namespace: "default",
 					},
 					Spec: v1alpha1.DatabaseUserReferenceSpec{
-						Cluster: corev1.TypedLocalObjectReference{
+						Cluster: v1alpha1.ClusterReference{
 							APIGroup: &v1alpha1.GroupVersion.Group,
 							Kind:     v1alpha1.DatabaseClusterKind,
 							Name:     dbCluster.Name,
 						},
 						Username: userName,
+						Database: "orders",
 					},
 				}
 				Expect(k8sClient.Create(ctx, dbUserRef)).To(Succeed())
@@ -68,6 +71,7 @@ var _ = Describe("DatabaseUserReference controller", func() {
 				Eventually(func(g Gomega) {
 					g.Expect(k8sClient.Get(ctx, dbUserRefLookupKey, createdDBUserRef)).To(Succeed())
 					g.Expect(createdDBUserRef.Status.Role).To(Equal(godoUser.Role))
+					g.Expect(meta.IsStatusConditionTrue(createdDBUserRef.Status.Conditions, v1alpha1.ConditionTypeReady)).To(BeTrue())
 				}, timeout, interval).Should(Succeed())
 			})
 
@@ -83,6 +87,9 @@ var _ = Describe("DatabaseUserReference controller", func() {
 				Expect(secret.OwnerReferences).To(ContainElement(dbUserRefOwnerReference))
 				Expect(string(secret.Data["username"])).To(Equal(userName))
 				Expect(secret.Data["password"]).NotTo(BeEmpty())
+				Expect(string(secret.Data["database"])).To(Equal("orders"))
+				Expect(string(secret.Data["uri"])).To(HavePrefix("mongodb+srv://" + userName + ":"))
+				Expect(string(secret.Data["uri"])).To(HaveSuffix("@host/orders?authSource=admin&tls=true"))
 			})
 
 			By("deleting the DatabaseUserReference object", func() {
@@ -129,7 +136,7 @@ var _ = Describe("DatabaseUserReference controller", func() {
 						Namespace: "default",
 					},
 					Spec: v1alpha1.DatabaseUserReferenceSpec{
-						Cluster: corev1.TypedLocalObjectReference{
+						Cluster: v1alpha1.ClusterReference{
 							APIGroup: &v1alpha1.GroupVersion.Group,
 							Kind:     v1alpha1.DatabaseClusterReferenceKind,
 							Name:     dbRef.Name,
@@ -190,5 +197,86 @@ var _ = Describe("DatabaseUserReference controller", func() {
 				}, timeout, interval).Should(Succeed())
 			})
 		})
+
+		It("should label and rename the credentials Secret", func() {
+			const (
+				userName = "renamed-secret-user"
+			)
+
+			var (
+				dbCluster          = mustCreateDatabaseClusterWithEngine("pg")
+				_                  = mustCreateGodoDBUser(dbCluster.Status.UUID, userName)
+				dbUserRefLookupKey = types.NamespacedName{
+					Name:      "renamed-secret-user-ref",
+					Namespace: "default",
+				}
+				defaultSecretKey = types.NamespacedName{
+					Name:      "renamed-secret-user-ref-credentials",
+					Namespace: "default",
+				}
+				renamedSecretKey = types.NamespacedName{
+					Name:      "app-db-credentials",
+					Namespace: "default",
+				}
+				createdDBUserRef = &v1alpha1.DatabaseUserReference{}
+			)
+
+			By("creating a DatabaseUserReference object with output metadata", func() {
+				dbUserRef := &v1alpha1.DatabaseUserReference{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.DatabaseUserReferenceKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      dbUserRefLookupKey.Name,
+						Namespace: dbUserRefLookupKey.Namespace,
+					},
+					Spec: v1alpha1.DatabaseUserReferenceSpec{
+						Cluster: v1alpha1.ClusterReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						Username: userName,
+						Output: &v1alpha1.UserOutput{
+							OutputMetadata: v1alpha1.OutputMetadata{
+								Labels:      map[string]string{"reflector": "enabled"},
+								Annotations: map[string]string{"example.com/owner": "team-a"},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, dbUserRef)).To(Succeed())
+			})
+
+			By("ensuring the credentials Secret has the labels and annotations", func() {
+				secret := &corev1.Secret{}
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, defaultSecretKey, secret)).To(Succeed())
+					g.Expect(secret.Labels).To(HaveKeyWithValue(v1alpha1.ManagedByLabel, v1alpha1.ManagedByValue))
+					g.Expect(secret.Labels).To(HaveKeyWithValue("reflector", "enabled"))
+					g.Expect(secret.Annotations).To(HaveKeyWithValue("example.com/owner", "team-a"))
+					g.Expect(secret.Type).To(Equal(corev1.SecretType("servicebinding.io/postgresql")))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("renaming the credentials Secret", func() {
+				Expect(k8sClient.Get(ctx, dbUserRefLookupKey, createdDBUserRef)).To(Succeed())
+				updatedDBUserRef := createdDBUserRef.DeepCopy()
+				updatedDBUserRef.Spec.Output.CredentialsSecretName = renamedSecretKey.Name
+				Expect(k8sClient.Patch(ctx, updatedDBUserRef, client.MergeFrom(createdDBUserRef))).To(Succeed())
+			})
+
+			By("ensuring the Secret is created under the new name and the old one is deleted", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, renamedSecretKey, &corev1.Secret{})).To(Succeed())
+					err := k8sClient.Get(ctx, defaultSecretKey, &corev1.Secret{})
+					g.Expect(kerrors.IsNotFound(err)).To(BeTrue())
+					g.Expect(k8sClient.Get(ctx, dbUserRefLookupKey, createdDBUserRef)).To(Succeed())
+					g.Expect(createdDBUserRef.Status.Outputs.CredentialsSecretName).To(Equal(renamedSecretKey.Name))
+					g.Expect(createdDBUserRef.Status.Binding).To(Equal(&corev1.LocalObjectReference{Name: renamedSecretKey.Name}))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
 	})
 })
@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/extgodo"
+)
+
+// Reasons for the Drifted condition.
+const (
+	driftReasonInSync     = "InSync"
+	driftReasonObserved   = "DriftObserved"
+	driftReasonCorrecting = "DriftCorrecting"
+)
+
+// clusterDrift is a set of DatabaseCluster fields that don't match the
+// database in DigitalOcean and are corrected together.
+type clusterDrift struct {
+	fields []v1alpha1.FieldDrift
+	// correct changes the database back to match the spec.
+	correct func(ctx context.Context) error
+}
+
+// detectClusterDrift compares each setting managed by a DatabaseCluster with
+// the database in DigitalOcean. Optional settings that aren't set in the spec
+// aren't compared.
+func detectClusterDrift(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster, db *godo.Database) ([]clusterDrift, error) {
+	var drifts []clusterDrift
+	if drift := sizeDrift(godoClient, cluster, db); drift != nil {
+		drifts = append(drifts, *drift)
+	}
+	firewall, err := firewallDrift(ctx, godoClient, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("getting firewall rules: %w", err)
+	}
+	if firewall != nil {
+		drifts = append(drifts, *firewall)
+	}
+	if drift := tagsDrift(godoClient, cluster, db); drift != nil {
+		drifts = append(drifts, *drift)
+	}
+	if drift := maintenanceWindowDrift(godoClient, cluster, db); drift != nil {
+		drifts = append(drifts, *drift)
+	}
+	config, err := configDrift(ctx, godoClient, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("getting config: %w", err)
+	}
+	if config != nil {
+		drifts = append(drifts, *config)
+	}
+	return drifts, nil
+}
+
+// sizeDrift compares the cluster's node size, node count and storage size.
+func sizeDrift(godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster, db *godo.Database) *clusterDrift {
+	spec := &cluster.Spec
+	var fields []v1alpha1.FieldDrift
+	if db.SizeSlug != spec.Size {
+		fields = append(fields, v1alpha1.FieldDrift{Field: "spec.size", Desired: spec.Size, Actual: db.SizeSlug})
+	}
+	if db.NumNodes != int(spec.NumNodes) {
+		fields = append(fields, v1alpha1.FieldDrift{
+			Field:   "spec.numNodes",
+			Desired: strconv.FormatInt(spec.NumNodes, 10),
+			Actual:  strconv.Itoa(db.NumNodes),
+		})
+	}
+	resizeReq := &godo.DatabaseResizeRequest{
+		SizeSlug: spec.Size,
+		NumNodes: int(spec.NumNodes),
+	}
+	if spec.StorageSizeMiB != nil {
+		resizeReq.StorageSizeMib = uint64(*spec.StorageSizeMiB)
+		if db.StorageSizeMib != resizeReq.StorageSizeMib {
+			fields = append(fields, v1alpha1.FieldDrift{
+				Field:   "spec.storageSizeMiB",
+				Desired: strconv.FormatUint(resizeReq.StorageSizeMib, 10),
+				Actual:  strconv.FormatUint(db.StorageSizeMib, 10),
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &clusterDrift{
+		fields: fields,
+		correct: func(ctx context.Context) error {
+			_, err := godoClient.Databases.Resize(ctx, cluster.Status.UUID, resizeReq)
+			recordClusterOperation("resize", err)
+			if err != nil {
+				return fmt.Errorf("resizing: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// firewallDrift compares the cluster's firewall rules.
+func firewallDrift(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) (*clusterDrift, error) {
+	if cluster.Spec.Firewall == nil {
+		return nil, nil
+	}
+	liveRules, _, err := godoClient.Databases.GetFirewallRules(ctx, cluster.Status.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var desired, actual []string
+	var rules []*godo.DatabaseFirewallRule
+	for _, rule := range cluster.Spec.Firewall.Rules {
+		desired = append(desired, rule.Type+":"+rule.Value)
+		rules = append(rules, &godo.DatabaseFirewallRule{Type: rule.Type, Value: rule.Value})
+	}
+	for _, rule := range liveRules {
+		actual = append(actual, rule.Type+":"+rule.Value)
+	}
+	desired = sortedUnique(desired)
+	actual = sortedUnique(actual)
+	if slices.Equal(desired, actual) {
+		return nil, nil
+	}
+
+	return &clusterDrift{
+		fields: []v1alpha1.FieldDrift{{
+			Field:   "spec.firewall.rules",
+			Desired: strings.Join(desired, ","),
+			Actual:  strings.Join(actual, ","),
+		}},
+		correct: func(ctx context.Context) error {
+			_, err := godoClient.Databases.UpdateFirewallRules(ctx, cluster.Status.UUID, &godo.DatabaseUpdateFirewallRulesRequest{
+				Rules: rules,
+			})
+			if err != nil {
+				return fmt.Errorf("updating firewall rules: %w", err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// tagsDrift compares the cluster's tags.
+func tagsDrift(godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster, db *godo.Database) *clusterDrift {
+	if len(cluster.Spec.Tags) == 0 {
+		return nil
+	}
+	desired := sortedUnique(cluster.Spec.Tags)
+	actual := sortedUnique(db.Tags)
+	if slices.Equal(desired, actual) {
+		return nil
+	}
+
+	resources := []godo.Resource{{ID: cluster.Status.UUID, Type: godo.DatabaseResourceType}}
+	return &clusterDrift{
+		fields: []v1alpha1.FieldDrift{{
+			Field:   "spec.tags",
+			Desired: strings.Join(desired, ","),
+			Actual:  strings.Join(actual, ","),
+		}},
+		correct: func(ctx context.Context) error {
+			for _, tag := range desired {
+				if slices.Contains(actual, tag) {
+					continue
+				}
+				// Tags have to exist before resources can be tagged with them.
+				if _, _, err := godoClient.Tags.Create(ctx, &godo.TagCreateRequest{Name: tag}); err != nil {
+					return fmt.Errorf("creating tag %q: %w", tag, err)
+				}
+				if _, err := godoClient.Tags.TagResources(ctx, tag, &godo.TagResourcesRequest{Resources: resources}); err != nil {
+					return fmt.Errorf("adding tag %q: %w", tag, err)
+				}
+			}
+			for _, tag := range actual {
+				if slices.Contains(desired, tag) {
+					continue
+				}
+				if _, err := godoClient.Tags.UntagResources(ctx, tag, &godo.UntagResourcesRequest{Resources: resources}); err != nil {
+					return fmt.Errorf("removing tag %q: %w", tag, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// maintenanceWindowDrift compares the cluster's maintenance window.
+func maintenanceWindowDrift(godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster, db *godo.Database) *clusterDrift {
+	window := cluster.Spec.MaintenanceWindow
+	if window == nil {
+		return nil
+	}
+	var actualDay, actualHour string
+	if db.MaintenanceWindow != nil {
+		actualDay = strings.ToLower(db.MaintenanceWindow.Day)
+		actualHour = maintenanceHour(db.MaintenanceWindow.Hour)
+	}
+
+	var fields []v1alpha1.FieldDrift
+	if window.Day != actualDay {
+		fields = append(fields, v1alpha1.FieldDrift{Field: "spec.maintenanceWindow.day", Desired: window.Day, Actual: actualDay})
+	}
+	if window.Hour != actualHour {
+		fields = append(fields, v1alpha1.FieldDrift{Field: "spec.maintenanceWindow.hour", Desired: window.Hour, Actual: actualHour})
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &clusterDrift{
+		fields: fields,
+		correct: func(ctx context.Context) error {
+			_, err := godoClient.Databases.UpdateMaintenance(ctx, cluster.Status.UUID, &godo.DatabaseUpdateMaintenanceRequest{
+				Day:  window.Day,
+				Hour: window.Hour,
+			})
+			if err != nil {
+				return fmt.Errorf("updating maintenance window: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// maintenanceHour returns an hour reported by the API, which may include
+// seconds, as HH:MM.
+func maintenanceHour(hour string) string {
+	parts := strings.Split(hour, ":")
+	if len(parts) < 2 {
+		return hour
+	}
+	return parts[0] + ":" + parts[1]
+}
+
+// configDrift compares the configuration options set in the cluster's spec.
+func configDrift(ctx context.Context, godoClient *godo.Client, cluster *v1alpha1.DatabaseCluster) (*clusterDrift, error) {
+	if len(cluster.Spec.Config) == 0 {
+		return nil, nil
+	}
+	options, err := json.Marshal(cluster.Spec.Config)
+	if err != nil {
+		return nil, err
+	}
+	config, err := extgodo.DecodeDatabaseConfig(cluster.Spec.Engine, options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec.config: %w", err)
+	}
+	live, err := extgodo.GetDatabaseConfig(ctx, godoClient, cluster.Spec.Engine, cluster.Status.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []v1alpha1.FieldDrift
+	for _, name := range slices.Sorted(maps.Keys(cluster.Spec.Config)) {
+		desired := cluster.Spec.Config[name].Raw
+		if jsonEqual(desired, live[name]) {
+			continue
+		}
+		fields = append(fields, v1alpha1.FieldDrift{
+			Field:   "spec.config." + name,
+			Desired: string(desired),
+			Actual:  string(live[name]),
+		})
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &clusterDrift{
+		fields: fields,
+		correct: func(ctx context.Context) error {
+			if err := extgodo.UpdateDatabaseConfig(ctx, godoClient, cluster.Status.UUID, config); err != nil {
+				return fmt.Errorf("updating config: %w", err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// jsonEqual returns whether two JSON values are equivalent.
+func jsonEqual(a, b []byte) bool {
+	var av, bv any
+	if err := json.Unmarshal(a, &av); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// sortedUnique returns a sorted copy of values without duplicates.
+func sortedUnique(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+// driftedFields returns all of the drifted fields.
+func driftedFields(drifts []clusterDrift) []v1alpha1.FieldDrift {
+	var fields []v1alpha1.FieldDrift
+	for _, drift := range drifts {
+		fields = append(fields, drift.fields...)
+	}
+	return fields
+}
+
+// setDriftedCondition records drift in an object's Drifted condition. reason
+// is ignored if there's no drift.
+func setDriftedCondition(conditions *[]metav1.Condition, generation int64, fields []v1alpha1.FieldDrift, reason string) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionTypeDrifted,
+		Status:             metav1.ConditionFalse,
+		Reason:             driftReasonInSync,
+		Message:            "The resource matches the spec.",
+		ObservedGeneration: generation,
+	}
+	if len(fields) > 0 {
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			names = append(names, field.Field)
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reason
+		condition.Message = "The resource doesn't match " + strings.Join(names, ", ") + "."
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
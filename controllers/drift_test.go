@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var _ = Describe("DatabaseCluster drift", func() {
+	It("should apply spec changes, and report or correct changes made outside the operator", func() {
+		clusterKey := types.NamespacedName{Name: "drift-db", Namespace: "default"}
+		dbCluster := &v1alpha1.DatabaseCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterKey.Name,
+				Namespace: clusterKey.Namespace,
+			},
+			Spec: v1alpha1.DatabaseClusterSpec{
+				Engine:         "pg",
+				Name:           "drift-db",
+				Version:        "16",
+				NumNodes:       1,
+				Size:           "size-slug",
+				Region:         "dev1",
+				StorageSizeMiB: pointer.Int64(20480),
+				Firewall: &v1alpha1.DatabaseFirewall{
+					Rules: []v1alpha1.FirewallRule{{Type: "ip_addr", Value: "192.0.2.1"}},
+				},
+				Tags: []string{"team-a", "prod"},
+				MaintenanceWindow: &v1alpha1.MaintenanceWindow{
+					Day:  "monday",
+					Hour: "03:00",
+				},
+				Config: map[string]apiextensionsv1.JSON{
+					"autovacuum_max_workers": {Raw: []byte("5")},
+				},
+				DriftPolicy: v1alpha1.DriftPolicyObserve,
+			},
+		}
+		Expect(k8sClient.Create(ctx, dbCluster)).To(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, dbCluster))).To(Succeed())
+		}()
+
+		// reconcile triggers a reconcile without changing the spec.
+		reconcile := func(value string) {
+			var cluster v1alpha1.DatabaseCluster
+			Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			if cluster.Annotations == nil {
+				cluster.Annotations = make(map[string]string)
+			}
+			cluster.Annotations["drift-test"] = value
+			Expect(k8sClient.Update(ctx, &cluster)).To(Succeed())
+		}
+
+		By("applying the settings that can't be set at creation once the cluster is online")
+		var cluster v1alpha1.DatabaseCluster
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			g.Expect(cluster.Status.AppliedGeneration).To(Equal(cluster.Generation))
+			g.Expect(cluster.Status.Drift).To(BeEmpty())
+		}, timeout, interval).Should(Succeed())
+		db, _, err := fakeDatabasesService.Get(ctx, cluster.Status.UUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.MaintenanceWindow.Day).To(Equal("monday"))
+		config, _, err := fakeDatabasesService.GetPostgreSQLConfig(ctx, cluster.Status.UUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.AutovacuumMaxWorkers).To(Equal(pointer.Int(5)))
+
+		By("reporting changes made outside the operator without correcting them")
+		_, err = fakeDatabasesService.UpdateFirewallRules(ctx, cluster.Status.UUID, &godo.DatabaseUpdateFirewallRulesRequest{
+			Rules: []*godo.DatabaseFirewallRule{{Type: "ip_addr", Value: "0.0.0.0/0"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = fakeDatabasesService.Resize(ctx, cluster.Status.UUID, &godo.DatabaseResizeRequest{
+			SizeSlug:       "size-slug",
+			NumNodes:       2,
+			StorageSizeMib: 30720,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		reconcile("observe")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			g.Expect(cluster.Status.Drift).To(ConsistOf(
+				v1alpha1.FieldDrift{Field: "spec.numNodes", Desired: "1", Actual: "2"},
+				v1alpha1.FieldDrift{Field: "spec.storageSizeMiB", Desired: "20480", Actual: "30720"},
+				v1alpha1.FieldDrift{Field: "spec.firewall.rules", Desired: "ip_addr:192.0.2.1", Actual: "ip_addr:0.0.0.0/0"},
+			))
+			drifted := meta.FindStatusCondition(cluster.Status.Conditions, v1alpha1.ConditionTypeDrifted)
+			g.Expect(drifted).NotTo(BeNil())
+			g.Expect(drifted.Status).To(Equal(metav1.ConditionTrue))
+			g.Expect(drifted.Reason).To(Equal(driftReasonObserved))
+		}, timeout, interval).Should(Succeed())
+		db, _, err = fakeDatabasesService.Get(ctx, cluster.Status.UUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.NumNodes).To(Equal(2))
+
+		By("applying spec changes in Observe mode")
+		Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+		cluster.Spec.Size = "larger-size-slug"
+		cluster.Spec.Tags = append(cluster.Spec.Tags, "observed")
+		Expect(k8sClient.Update(ctx, &cluster)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			g.Expect(cluster.Status.AppliedGeneration).To(Equal(cluster.Generation))
+			g.Expect(cluster.Status.Drift).To(BeEmpty())
+		}, timeout, interval).Should(Succeed())
+		db, _, err = fakeDatabasesService.Get(ctx, cluster.Status.UUID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.SizeSlug).To(Equal("larger-size-slug"))
+		Expect(db.NumNodes).To(Equal(1))
+		Expect(db.Tags).To(ConsistOf("team-a", "prod", "observed"))
+
+		By("correcting changes made outside the operator in Enforce mode")
+		Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+		cluster.Spec.DriftPolicy = v1alpha1.DriftPolicyEnforce
+		Expect(k8sClient.Update(ctx, &cluster)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			g.Expect(cluster.Status.AppliedGeneration).To(Equal(cluster.Generation))
+		}, timeout, interval).Should(Succeed())
+		_, err = fakeDatabasesService.UpdateFirewallRules(ctx, cluster.Status.UUID, &godo.DatabaseUpdateFirewallRulesRequest{
+			Rules: []*godo.DatabaseFirewallRule{{Type: "ip_addr", Value: "0.0.0.0/0"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		reconcile("enforce")
+		Eventually(func(g Gomega) {
+			rules, _, err := fakeDatabasesService.GetFirewallRules(ctx, cluster.Status.UUID)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(rules).To(HaveLen(1))
+			g.Expect(rules[0].Value).To(Equal("192.0.2.1"))
+		}, timeout, interval).Should(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, clusterKey, &cluster)).To(Succeed())
+			g.Expect(cluster.Status.Drift).To(BeEmpty())
+			drifted := meta.FindStatusCondition(cluster.Status.Conditions, v1alpha1.ConditionTypeDrifted)
+			g.Expect(drifted).NotTo(BeNil())
+			g.Expect(drifted.Status).To(Equal(metav1.ConditionFalse))
+		}, timeout, interval).Should(Succeed())
+	})
+})
@@ -12,8 +12,11 @@ import (
 )
 
 func mustCreateDatabaseCluster() *v1alpha1.DatabaseCluster {
+	return mustCreateDatabaseClusterWithEngine("mongodb")
+}
+
+func mustCreateDatabaseClusterWithEngine(dbEngine string) *v1alpha1.DatabaseCluster {
 	const (
-		dbEngine   = "mongodb"
 		dbVersion  = "5.0"
 		dbNumNodes = 1
 		dbSize     = "size-slug"
@@ -0,0 +1,334 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/godo"
+	"github.com/google/go-cmp/cmp"
+)
+
+// KafkaSchemaSubjectReconciler reconciles a KafkaSchemaSubject object
+type KafkaSchemaSubjectReconciler struct {
+	client.Client
+	// APIReader reads from the API server rather than the cache, which only
+	// holds the ConfigMaps generated by the operator. It's used to read schema
+	// ConfigMaps.
+	APIReader   client.Reader
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+	Recorder    events.EventRecorder
+	// Metadata is a cache from AddMetadataCache. The metadata of ConfigMaps
+	// is watched through it to pick up changes to schemas.
+	Metadata cache.Cache
+}
+
+// schemaConfigMapField is the field index of KafkaSchemaSubjects by the name
+// of the ConfigMap holding their schema.
+const schemaConfigMapField = "spec.schemaConfigMapRef.name"
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=kafkaschemasubjects,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=kafkaschemasubjects/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=kafkaschemasubjects/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *KafkaSchemaSubjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ll := log.FromContext(ctx)
+	ll.Info("reconciling KafkaSchemaSubject", "name", req.Name)
+
+	var subject v1alpha1.KafkaSchemaSubject
+	err := r.Get(ctx, req.NamespacedName, &subject)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to get KafkaSchemaSubject %s: %s", req.NamespacedName, err)
+	}
+
+	originalSubject := subject.DeepCopy()
+	inDeletion := !subject.DeletionTimestamp.IsZero()
+
+	defer func() {
+		var (
+			updated = false
+			errs    []error
+		)
+
+		if !cmp.Equal(subject.Finalizers, originalSubject.Finalizers) {
+			ll.Info("updating KafkaSchemaSubject finalizers")
+			if err := r.Patch(ctx, subject.DeepCopy(), client.MergeFrom(originalSubject)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update KafkaSchemaSubject: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if diff := cmp.Diff(subject.Status, originalSubject.Status); diff != "" {
+			ll.WithValues("diff", diff).Info("status diff detected")
+
+			if err := r.Status().Patch(ctx, &subject, client.MergeFrom(originalSubject)); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update KafkaSchemaSubject status: %s", err))
+			} else {
+				updated = true
+			}
+		}
+
+		if len(errs) == 0 {
+			if updated {
+				ll.Info("KafkaSchemaSubject update succeeded")
+			} else {
+				ll.Info("no KafkaSchemaSubject update necessary")
+			}
+		}
+
+		retErr = utilerror.NewAggregate(append([]error{retErr}, errs...))
+	}()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, subject.Namespace, subject.Spec.ProviderConfigRef)
+	if err != nil {
+		if inDeletion && releaseWithoutCredentials(ctx, r.Recorder, &subject, err) {
+			return result, nil
+		}
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	if inDeletion {
+		ll.Info("deleting KafkaSchemaSubject")
+		if subject.Status.ClusterUUID == "" {
+			// Subject was never actually registered; nothing to do.
+			controllerutil.RemoveFinalizer(&subject, finalizerName)
+			return ctrl.Result{}, nil
+		}
+		return r.reconcileDeletedSubject(ctx, godoClient, &subject)
+	}
+
+	// If we haven't noted the cluster's UUID yet, look it up. Subsequent
+	// reconciles won't have to do this.
+	if subject.Status.ClusterUUID == "" {
+		cluster, err := getReferencedCluster(ctx, r.Client, subject.Namespace, subject.Spec.Cluster)
+		if err != nil {
+			return result, err
+		}
+
+		// The schema registry isn't available until the cluster is online,
+		// which triggers another reconcile.
+		if !cluster.isReady() {
+			ll.Info("database is still creating; waiting to register schema")
+			return ctrl.Result{RequeueAfter: clusterNotReadyRequeueTime}, nil
+		}
+
+		subject.Status.ClusterUUID = cluster.UUID
+	}
+
+	ll.Info("reconciling KafkaSchemaSubject")
+	return r.reconcileSubject(ctx, godoClient, &subject)
+}
+
+func (r *KafkaSchemaSubjectReconciler) reconcileSubject(ctx context.Context, godoClient *godo.Client, subject *v1alpha1.KafkaSchemaSubject) (ctrl.Result, error) {
+	var (
+		clusterUUID = subject.Status.ClusterUUID
+		subjectName = subject.Spec.SubjectName
+		ll          = log.FromContext(ctx).WithValues(
+			"cluster_uuid", clusterUUID,
+			"subject_name", subjectName,
+		)
+	)
+
+	schema, err := r.desiredSchema(ctx, subject)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	existing, resp, err := godoClient.Databases.GetKafkaSchemaRegistry(ctx, clusterUUID, subjectName)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return ctrl.Result{}, fmt.Errorf("checking for existing schema subject: %w", err)
+	}
+
+	// Registering a schema that's identical to the latest version is a no-op
+	// in the schema registry, so we only need to compare the definitions to
+	// avoid unnecessary API calls.
+	if err != nil || !schemasEqual(existing.Schema, schema) || existing.SchemaType != subject.Spec.SchemaType {
+		ll.Info("registering schema")
+		existing, _, err = godoClient.Databases.CreateKafkaSchemaRegistry(ctx, clusterUUID, &godo.DatabaseKafkaSchemaRegistryRequest{
+			SubjectName: subjectName,
+			SchemaType:  subject.Spec.SchemaType,
+			Schema:      schema,
+		})
+		if err != nil {
+			ll.Error(err, "unable to register schema")
+			return ctrl.Result{}, fmt.Errorf("registering schema: %w", err)
+		}
+	}
+
+	controllerutil.AddFinalizer(subject, finalizerName)
+	subject.Status.SchemaID = int64(existing.SchemaID)
+
+	if subject.Spec.CompatibilityLevel != "" {
+		subjectConfig, _, err := godoClient.Databases.GetKafkaSchemaRegistrySubjectConfig(ctx, clusterUUID, subjectName)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("getting schema subject config: %w", err)
+		}
+		if subjectConfig.CompatibilityLevel != subject.Spec.CompatibilityLevel {
+			ll.Info("updating compatibility level",
+				"current_level", subjectConfig.CompatibilityLevel,
+				"desired_level", subject.Spec.CompatibilityLevel)
+			subjectConfig, _, err = godoClient.Databases.UpdateKafkaSchemaRegistrySubjectConfig(ctx, clusterUUID, subjectName, &godo.DatabaseKafkaSchemaRegistryConfig{
+				CompatibilityLevel: subject.Spec.CompatibilityLevel,
+			})
+			if err != nil {
+				ll.Error(err, "unable to update compatibility level")
+				return ctrl.Result{}, fmt.Errorf("updating schema subject config: %w", err)
+			}
+		}
+		subject.Status.CompatibilityLevel = subjectConfig.CompatibilityLevel
+	}
+
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// schemasEqual returns whether two schema definitions are the same. The schema
+// registry returns Avro and JSON schemas in a canonical, compact form, so
+// definitions that are both JSON are compared by value rather than by their
+// formatting.
+func schemasEqual(registered, desired string) bool {
+	if registered == desired {
+		return true
+	}
+	var registeredValue, desiredValue any
+	if json.Unmarshal([]byte(registered), &registeredValue) != nil || json.Unmarshal([]byte(desired), &desiredValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(registeredValue, desiredValue)
+}
+
+// desiredSchema returns the schema definition for the subject, either from the
+// spec or from the referenced ConfigMap.
+func (r *KafkaSchemaSubjectReconciler) desiredSchema(ctx context.Context, subject *v1alpha1.KafkaSchemaSubject) (string, error) {
+	ref := subject.Spec.SchemaConfigMapRef
+	if ref == nil {
+		return subject.Spec.Schema, nil
+	}
+
+	var (
+		cm   corev1.ConfigMap
+		cmNN = types.NamespacedName{
+			Namespace: subject.Namespace,
+			Name:      ref.Name,
+		}
+	)
+	if err := r.APIReader.Get(ctx, cmNN, &cm); err != nil {
+		return "", fmt.Errorf("failed to get schema ConfigMap %s: %s", cmNN.Name, err)
+	}
+	schema, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("schema ConfigMap %s has no key %q", cmNN.Name, ref.Key)
+	}
+
+	return schema, nil
+}
+
+func (r *KafkaSchemaSubjectReconciler) reconcileDeletedSubject(ctx context.Context, godoClient *godo.Client, subject *v1alpha1.KafkaSchemaSubject) (ctrl.Result, error) {
+	ll := log.FromContext(ctx)
+	ll = ll.WithValues(
+		"cluster_uuid", subject.Status.ClusterUUID,
+		"subject_name", subject.Spec.SubjectName,
+	)
+
+	resp, err := godoClient.Databases.DeleteKafkaSchemaRegistry(ctx, subject.Status.ClusterUUID, subject.Spec.SubjectName)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		ll.Error(err, "unable to delete schema subject")
+		return ctrl.Result{}, fmt.Errorf("deleting schema subject: %w", err)
+	}
+	controllerutil.RemoveFinalizer(subject, finalizerName)
+
+	return ctrl.Result{}, nil
+}
+
+// subjectsForConfigMap returns requests for the KafkaSchemaSubjects whose
+// schema is in a ConfigMap.
+func (r *KafkaSchemaSubjectReconciler) subjectsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	var subjects v1alpha1.KafkaSchemaSubjectList
+	if err := r.List(ctx, &subjects, client.InNamespace(obj.GetNamespace()), client.MatchingFields{schemaConfigMapField: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list KafkaSchemaSubjects for ConfigMap", "configmap", obj.GetName())
+		return nil
+	}
+
+	reqs := make([]reconcile.Request, 0, len(subjects.Items))
+	for _, subject := range subjects.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&subject)})
+	}
+	return reqs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KafkaSchemaSubjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &v1alpha1.KafkaSchemaSubject{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &v1alpha1.KafkaSchemaSubjectList{}))
+
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.KafkaSchemaSubject{}, schemaConfigMapField, func(obj client.Object) []string {
+		if ref := obj.(*v1alpha1.KafkaSchemaSubject).Spec.SchemaConfigMapRef; ref != nil {
+			return []string{ref.Name}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("indexing %s: %w", schemaConfigMapField, err)
+	}
+
+	// Schema ConfigMaps aren't in the manager's cache, so watch the metadata
+	// of every ConfigMap instead. The metadata changes along with the
+	// contents, so this is enough to notice changed schemas.
+	configMap := &metav1.PartialObjectMetadata{}
+	configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.KafkaSchemaSubject{}).
+		Watches(&v1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&v1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
+		WatchesRawSource(source.Kind[client.Object](r.Metadata, configMap, handler.EnqueueRequestsFromMapFunc(r.subjectsForConfigMap))).
+		Complete(r)
+}
@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("KafkaSchemaSubject controller", func() {
+	Context("When reconciling a KafkaSchemaSubject", func() {
+		It("should manage the lifecycle of an inline schema subject", func() {
+			const (
+				subjectName = "orders-value"
+				schemaV1    = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+				schemaV2    = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"total","type":["null","long"],"default":null}]}`
+			)
+
+			var (
+				dbCluster        = mustCreateDatabaseClusterWithEngine("kafka")
+				subjectLookupKey = types.NamespacedName{
+					Name:      "orders-value-subject",
+					Namespace: "default",
+				}
+				createdSubject = &v1alpha1.KafkaSchemaSubject{}
+			)
+
+			By("creating a KafkaSchemaSubject object", func() {
+				subject := &v1alpha1.KafkaSchemaSubject{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.KafkaSchemaSubjectKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "orders-value-subject",
+						Namespace: "default",
+					},
+					Spec: v1alpha1.KafkaSchemaSubjectSpec{
+						Cluster: corev1.TypedLocalObjectReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						SubjectName:        subjectName,
+						SchemaType:         "AVRO",
+						Schema:             schemaV1,
+						CompatibilityLevel: "FULL",
+					},
+				}
+				Expect(k8sClient.Create(ctx, subject)).To(Succeed())
+			})
+
+			By("ensuring the schema is registered in godo", func() {
+				Eventually(func(g Gomega) {
+					registered, _, err := fakeDatabasesService.GetKafkaSchemaRegistry(ctx, dbCluster.Status.UUID, subjectName)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(registered.Schema).To(Equal(schemaV1))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("ensuring the KafkaSchemaSubject status gets filled in", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, subjectLookupKey, createdSubject)).To(Succeed())
+					g.Expect(createdSubject.Status.ClusterUUID).To(Equal(dbCluster.Status.UUID))
+					g.Expect(createdSubject.Status.SchemaID).NotTo(BeZero())
+					g.Expect(createdSubject.Status.CompatibilityLevel).To(Equal("FULL"))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("updating the schema", func() {
+				updatedSubject := createdSubject.DeepCopy()
+				updatedSubject.Spec.Schema = schemaV2
+				Expect(k8sClient.Patch(ctx, updatedSubject, client.MergeFrom(createdSubject))).To(Succeed())
+
+				Eventually(func(g Gomega) {
+					registered, _, err := fakeDatabasesService.GetKafkaSchemaRegistry(ctx, dbCluster.Status.UUID, subjectName)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(registered.Schema).To(Equal(schemaV2))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("deleting the KafkaSchemaSubject object", func() {
+				Expect(k8sClient.Delete(ctx, createdSubject)).To(Succeed())
+				// Wait for the object to go away.
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, subjectLookupKey, createdSubject)
+					return kerrors.IsNotFound(err)
+				}, timeout, interval).Should(BeTrue())
+			})
+
+			By("ensuring the subject is deleted in godo", func() {
+				Eventually(func() error {
+					_, _, err := fakeDatabasesService.GetKafkaSchemaRegistry(ctx, dbCluster.Status.UUID, subjectName)
+					return err
+				}, timeout, interval).ShouldNot(Succeed())
+			})
+		})
+
+		It("should register a schema from a ConfigMap", func() {
+			const (
+				subjectName = "payments-value"
+				schema      = `{"type":"object","properties":{"id":{"type":"string"}}}`
+				newSchema   = `{"type":"object","properties":{"id":{"type":"string"},"amount":{"type":"number"}}}`
+			)
+
+			dbCluster := mustCreateDatabaseClusterWithEngine("kafka")
+
+			By("creating the schema ConfigMap", func() {
+				cm := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "payments-schema",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"schema.json": schema,
+					},
+				}
+				Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+			})
+
+			By("creating a KafkaSchemaSubject object referencing the ConfigMap", func() {
+				subject := &v1alpha1.KafkaSchemaSubject{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.KafkaSchemaSubjectKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "payments-value-subject",
+						Namespace: "default",
+					},
+					Spec: v1alpha1.KafkaSchemaSubjectSpec{
+						Cluster: corev1.TypedLocalObjectReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						SubjectName: subjectName,
+						SchemaType:  "JSON",
+						SchemaConfigMapRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "payments-schema",
+							},
+							Key: "schema.json",
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, subject)).To(Succeed())
+			})
+
+			By("ensuring the schema is registered in godo", func() {
+				Eventually(func(g Gomega) {
+					registered, _, err := fakeDatabasesService.GetKafkaSchemaRegistry(ctx, dbCluster.Status.UUID, subjectName)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(registered.Schema).To(Equal(schema))
+					g.Expect(registered.SchemaType).To(Equal("JSON"))
+				}, timeout, interval).Should(Succeed())
+			})
+
+			By("updating the schema in the ConfigMap", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "payments-schema", Namespace: "default"}, cm)).To(Succeed())
+				updatedCM := cm.DeepCopy()
+				updatedCM.Data["schema.json"] = newSchema
+				Expect(k8sClient.Patch(ctx, updatedCM, client.MergeFrom(cm))).To(Succeed())
+			})
+
+			By("ensuring the new schema is registered in godo", func() {
+				Eventually(func(g Gomega) {
+					registered, _, err := fakeDatabasesService.GetKafkaSchemaRegistry(ctx, dbCluster.Status.UUID, subjectName)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(registered.Schema).To(Equal(newSchema))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
+
+		It("should not re-register a schema that only differs in formatting", func() {
+			const (
+				subjectName = "refunds-value"
+				schema      = `{
+  "type": "record",
+  "name": "Refund",
+  "fields": [{"name": "id", "type": "string"}]
+}`
+				reformattedSchema = `{"type": "record", "name": "Refund",
+	"fields": [ {"name": "id", "type": "string"} ]}`
+			)
+
+			var (
+				dbCluster        = mustCreateDatabaseClusterWithEngine("kafka")
+				subjectLookupKey = types.NamespacedName{
+					Name:      "refunds-value-subject",
+					Namespace: "default",
+				}
+				createdSubject = &v1alpha1.KafkaSchemaSubject{}
+			)
+
+			By("creating a KafkaSchemaSubject object", func() {
+				subject := &v1alpha1.KafkaSchemaSubject{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      subjectLookupKey.Name,
+						Namespace: subjectLookupKey.Namespace,
+					},
+					Spec: v1alpha1.KafkaSchemaSubjectSpec{
+						Cluster: corev1.TypedLocalObjectReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						SubjectName: subjectName,
+						SchemaType:  "AVRO",
+						Schema:      schema,
+					},
+				}
+				Expect(k8sClient.Create(ctx, subject)).To(Succeed())
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, subjectLookupKey, createdSubject)).To(Succeed())
+					g.Expect(createdSubject.Status.SchemaID).NotTo(BeZero())
+				}, timeout, interval).Should(Succeed())
+				Expect(fakeDatabasesService.KafkaSchemaRegistrations(dbCluster.Status.UUID, subjectName)).To(Equal(1))
+			})
+
+			By("reformatting the schema", func() {
+				updatedSubject := createdSubject.DeepCopy()
+				updatedSubject.Spec.Schema = reformattedSchema
+				Expect(k8sClient.Patch(ctx, updatedSubject, client.MergeFrom(createdSubject))).To(Succeed())
+
+				Consistently(func() int {
+					return fakeDatabasesService.KafkaSchemaRegistrations(dbCluster.Status.UUID, subjectName)
+				}, 2*time.Second, interval).Should(Equal(1))
+			})
+		})
+	})
+})
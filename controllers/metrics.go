@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+// Phases reported by the do_operator_objects metric.
+const (
+	phasePending     = "Pending"
+	phaseProvisioned = "Provisioned"
+	phaseDeleting    = "Deleting"
+)
+
+// Results reported by the do_operator_database_cluster_operations_total
+// metric.
+const (
+	operationSuccess = "success"
+	operationError   = "error"
+)
+
+var databaseClusterOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "do_operator_database_cluster_operations_total",
+	Help: "Number of create, resize and delete operations requested for database clusters, by result.",
+}, []string{"operation", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(databaseClusterOperations)
+}
+
+// recordClusterOperation counts a database cluster operation that returned
+// err.
+func recordClusterOperation(operation string, err error) {
+	result := operationSuccess
+	if err != nil {
+		result = operationError
+	}
+	databaseClusterOperations.WithLabelValues(operation, result).Inc()
+}
+
+var (
+	objectsDesc = prometheus.NewDesc(
+		"do_operator_objects",
+		"Number of objects managed by the operator, by kind and phase.",
+		[]string{"kind", "phase"}, nil,
+	)
+	databaseClustersDesc = prometheus.NewDesc(
+		"do_operator_database_clusters",
+		"Number of database clusters, by kind and the status reported by DigitalOcean.",
+		[]string{"kind", "status"}, nil,
+	)
+)
+
+// collectTimeout bounds how long a scrape waits to list objects.
+const collectTimeout = 10 * time.Second
+
+// ObjectsCollector is a prometheus.Collector that reports how many of the
+// operator's objects exist in each phase. Objects are listed at scrape time, so
+// reader should be backed by the manager's cache.
+type ObjectsCollector struct {
+	reader client.Reader
+}
+
+// NewObjectsCollector returns an ObjectsCollector that lists objects with
+// reader.
+func NewObjectsCollector(reader client.Reader) *ObjectsCollector {
+	return &ObjectsCollector{reader: reader}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ObjectsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectsDesc
+	ch <- databaseClustersDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ObjectsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	var clusters v1alpha1.DatabaseClusterList
+	if c.list(ctx, ch, &clusters) {
+		phases := make(map[string]float64)
+		statuses := make(map[string]float64)
+		for _, cluster := range clusters.Items {
+			phases[objectPhase(&cluster, cluster.Status.UUID != "")]++
+			statuses[clusterStatus(cluster.Status.Status)]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseClusterKind, phases)
+		c.send(ch, databaseClustersDesc, v1alpha1.DatabaseClusterKind, statuses)
+	}
+
+	var clusterRefs v1alpha1.DatabaseClusterReferenceList
+	if c.list(ctx, ch, &clusterRefs) {
+		phases := make(map[string]float64)
+		statuses := make(map[string]float64)
+		for _, ref := range clusterRefs.Items {
+			phases[objectPhase(&ref, ref.Status.Status != "")]++
+			statuses[clusterStatus(ref.Status.Status)]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseClusterReferenceKind, phases)
+		c.send(ch, databaseClustersDesc, v1alpha1.DatabaseClusterReferenceKind, statuses)
+	}
+
+	var users v1alpha1.DatabaseUserList
+	if c.list(ctx, ch, &users) {
+		phases := make(map[string]float64)
+		for _, user := range users.Items {
+			phases[objectPhase(&user, user.Status.ClusterUUID != "")]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseUserKind, phases)
+	}
+
+	var userRefs v1alpha1.DatabaseUserReferenceList
+	if c.list(ctx, ch, &userRefs) {
+		phases := make(map[string]float64)
+		for _, ref := range userRefs.Items {
+			phases[objectPhase(&ref, ref.Status.ClusterUUID != "")]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseUserReferenceKind, phases)
+	}
+
+	var sinks v1alpha1.DatabaseLogsinkList
+	if c.list(ctx, ch, &sinks) {
+		phases := make(map[string]float64)
+		for _, sink := range sinks.Items {
+			phases[objectPhase(&sink, sink.Status.SinkID != "")]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseLogsinkKind, phases)
+	}
+
+	var migrations v1alpha1.DatabaseOnlineMigrationList
+	if c.list(ctx, ch, &migrations) {
+		phases := make(map[string]float64)
+		for _, migration := range migrations.Items {
+			phases[objectPhase(&migration, migration.Status.MigrationID != "")]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.DatabaseOnlineMigrationKind, phases)
+	}
+
+	var subjects v1alpha1.KafkaSchemaSubjectList
+	if c.list(ctx, ch, &subjects) {
+		phases := make(map[string]float64)
+		for _, subject := range subjects.Items {
+			phases[objectPhase(&subject, subject.Status.SchemaID != 0)]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.KafkaSchemaSubjectKind, phases)
+	}
+
+	var policies v1alpha1.OpenSearchIndexPolicyList
+	if c.list(ctx, ch, &policies) {
+		phases := make(map[string]float64)
+		for _, policy := range policies.Items {
+			phases[objectPhase(&policy, policy.Status.LastEvaluatedAt != nil)]++
+		}
+		c.send(ch, objectsDesc, v1alpha1.OpenSearchIndexPolicyKind, phases)
+	}
+}
+
+// list lists objects into list, reporting an invalid metric to the registry
+// if that fails. It returns whether the list succeeded.
+func (c *ObjectsCollector) list(ctx context.Context, ch chan<- prometheus.Metric, list client.ObjectList) bool {
+	if err := c.reader.List(ctx, list); err != nil {
+		ch <- prometheus.NewInvalidMetric(objectsDesc, err)
+		return false
+	}
+	return true
+}
+
+// send reports a gauge for each value of the desc's second label.
+func (c *ObjectsCollector) send(ch chan<- prometheus.Metric, desc *prometheus.Desc, kind string, counts map[string]float64) {
+	for label, count := range counts {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, count, kind, label)
+	}
+}
+
+// objectPhase returns the phase of obj, given whether it has been provisioned
+// in DigitalOcean.
+func objectPhase(obj client.Object, provisioned bool) string {
+	switch {
+	case !obj.GetDeletionTimestamp().IsZero():
+		return phaseDeleting
+	case provisioned:
+		return phaseProvisioned
+	default:
+		return phasePending
+	}
+}
+
+// clusterStatus returns the status label for a database cluster status.
+func clusterStatus(status string) string {
+	if status == "" {
+		return "unknown"
+	}
+	return status
+}
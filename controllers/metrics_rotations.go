@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/godo"
+)
+
+// MetricsCredentialsRotations tracks rotations of the metrics credentials.
+// The credentials are shared by every database in a DigitalOcean account, so
+// once one cluster rotates them, the other clusters using the same provider
+// config adopt that rotation rather than rotating again, and every object
+// with a metrics Secret is reconciled to pick up the new credentials.
+type MetricsCredentialsRotations struct {
+	mu sync.Mutex
+	// rotatedAt is when the credentials were last rotated through each
+	// client, which stands in for its account.
+	rotatedAt   map[*godo.Client]time.Time
+	subscribers []chan event.TypedGenericEvent[*godo.Client]
+}
+
+// NewMetricsCredentialsRotations returns an empty MetricsCredentialsRotations.
+func NewMetricsCredentialsRotations() *MetricsCredentialsRotations {
+	return &MetricsCredentialsRotations{rotatedAt: make(map[*godo.Client]time.Time)}
+}
+
+// lastRotated returns when the credentials were last rotated through
+// godoClient since the operator started, if they have been.
+func (m *MetricsCredentialsRotations) lastRotated(godoClient *godo.Client) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	at, ok := m.rotatedAt[godoClient]
+	return at, ok
+}
+
+// rotated records that the credentials were rotated through godoClient and
+// notifies the subscribers. A subscriber that's already been notified of a
+// rotation it hasn't handled yet isn't notified again.
+func (m *MetricsCredentialsRotations) rotated(godoClient *godo.Client, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotatedAt[godoClient] = at
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- event.TypedGenericEvent[*godo.Client]{Object: godoClient}:
+		default:
+		}
+	}
+}
+
+// source returns a source that enqueues the requests returned by fn for the
+// client of each rotation.
+func (m *MetricsCredentialsRotations) source(fn handler.TypedMapFunc[*godo.Client, reconcile.Request]) source.Source {
+	ch := make(chan event.TypedGenericEvent[*godo.Client], 1)
+	m.mu.Lock()
+	m.subscribers = append(m.subscribers, ch)
+	m.mu.Unlock()
+	return source.Channel(ch, handler.TypedEnqueueRequestsFromMapFunc(fn))
+}
+
+// metricsSecretOwners returns a map function for a rotations source that lists
+// objects into list and returns requests for those that have a metrics Secret
+// and use the rotated client. providerConfigRef returns an object's provider
+// config reference.
+func metricsSecretOwners(c client.Client, godoClients *apitoken.ClientCache, list client.ObjectList, providerConfigRef func(client.Object) *corev1.LocalObjectReference) handler.TypedMapFunc[*godo.Client, reconcile.Request] {
+	return func(ctx context.Context, godoClient *godo.Client) []reconcile.Request {
+		ll := log.FromContext(ctx)
+
+		objList := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, objList); err != nil {
+			ll.Error(err, "unable to list objects with metrics Secrets")
+			return nil
+		}
+		objs, err := meta.ExtractList(objList)
+		if err != nil {
+			ll.Error(err, "unable to list objects with metrics Secrets")
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		for _, o := range objs {
+			obj := o.(client.Object)
+			secretKey := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName() + metricsCredentialsSecretSuffix}
+			if err := c.Get(ctx, secretKey, &corev1.Secret{}); err != nil {
+				continue
+			}
+			objClient, err := godoClients.ClientFor(ctx, obj.GetNamespace(), providerConfigRef(obj))
+			if err != nil || objClient != godoClient {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	metricsCredentialsSecretSuffix = "-metrics-credentials"
+	metricsScrapeTargetSuffix      = "-metrics"
+	metricsPortName                = "metrics"
+
+	metricsUsernameKey = "username"
+	metricsPasswordKey = "password"
+	metricsCAKey       = "ca.crt"
+
+	// metricsTargetLabel is set on the metrics Service so that a
+	// ServiceMonitor can select it.
+	metricsTargetLabel = "databases.digitalocean.com/metrics-target"
+)
+
+var (
+	scrapeConfigGVK = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "ScrapeConfig",
+	}
+	serviceMonitorGVK = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "ServiceMonitor",
+	}
+
+	// lookupMetricsHost resolves the host of a metrics endpoint to the IP
+	// addresses used in the metrics Endpoints. It's a variable so we can
+	// replace it in tests.
+	lookupMetricsHost = net.DefaultResolver.LookupHost
+)
+
+// metricsObjectsForDB returns the objects needed for Prometheus to scrape the
+// database's metrics endpoints: a Secret holding the metrics credentials and
+// a scrape target. The scrape target is a ScrapeConfig if the Prometheus
+// Operator's ScrapeConfig CRD is installed. Otherwise, it's a selectorless
+// Service and Endpoints pair, which is annotated for annotation-based
+// discovery and selected by a ServiceMonitor if that CRD is installed.
+func metricsObjectsForDB(ctx context.Context, c client.Client, godoClient *godo.Client, owner client.Object, db *godo.Database) ([]client.Object, error) {
+	if len(db.MetricsEndpoints) == 0 {
+		return nil, nil
+	}
+
+	creds, _, err := godoClient.Databases.GetMetricsCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting metrics credentials: %w", err)
+	}
+	ca, _, err := godoClient.Databases.GetCA(ctx, db.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting database CA: %w", err)
+	}
+
+	objs := []client.Object{metricsCredentialsSecretForDB(owner, creds, ca)}
+
+	haveScrapeConfig, err := haveKind(c, scrapeConfigGVK)
+	if err != nil {
+		return nil, err
+	}
+	if haveScrapeConfig {
+		return append(objs, metricsScrapeConfigForDB(owner, db)), nil
+	}
+
+	endpoints, err := metricsEndpointsForDB(ctx, owner, db)
+	if err != nil {
+		return nil, err
+	}
+	objs = append(objs, metricsServiceForDB(owner, db), endpoints)
+
+	haveServiceMonitor, err := haveKind(c, serviceMonitorGVK)
+	if err != nil {
+		return nil, err
+	}
+	if haveServiceMonitor {
+		objs = append(objs, metricsServiceMonitorForDB(owner, db))
+	}
+
+	return objs, nil
+}
+
+// haveKind returns whether the API server serves the given kind.
+func haveKind(c client.Client, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("looking up %s: %w", gvk, err)
+	}
+	return true, nil
+}
+
+func metricsCredentialsSecretForDB(owner client.Object, creds *godo.DatabaseMetricsCredentials, ca *godo.DatabaseCA) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      owner.GetName() + metricsCredentialsSecretSuffix,
+		},
+		StringData: map[string]string{
+			metricsUsernameKey: creds.BasicAuthUsername,
+			metricsPasswordKey: creds.BasicAuthPassword,
+			metricsCAKey:       string(ca.Certificate),
+		},
+	}
+}
+
+func metricsScrapeConfigForDB(owner client.Object, db *godo.Database) *unstructured.Unstructured {
+	var targets []any
+	for _, endpoint := range db.MetricsEndpoints {
+		targets = append(targets, net.JoinHostPort(endpoint.Host, strconv.Itoa(endpoint.Port)))
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"scheme":    "HTTPS",
+			"basicAuth": metricsBasicAuth(owner),
+			"tlsConfig": metricsTLSConfig(owner, ""),
+			"staticConfigs": []any{
+				map[string]any{
+					"targets": targets,
+					"labels": map[string]any{
+						"db_uuid": db.ID,
+					},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(scrapeConfigGVK)
+	obj.SetNamespace(owner.GetNamespace())
+	obj.SetName(owner.GetName() + metricsScrapeTargetSuffix)
+
+	return obj
+}
+
+func metricsServiceForDB(owner client.Object, db *godo.Database) *corev1.Service {
+	port := db.MetricsEndpoints[0].Port
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      owner.GetName() + metricsScrapeTargetSuffix,
+			Labels: map[string]string{
+				metricsTargetLabel: owner.GetName(),
+			},
+			Annotations: map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/scheme": "https",
+				"prometheus.io/port":   strconv.Itoa(port),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			// The Endpoints are managed by the operator, so the Service is
+			// headless and has no selector.
+			ClusterIP: corev1.ClusterIPNone,
+			Ports: []corev1.ServicePort{{
+				Name:     metricsPortName,
+				Protocol: corev1.ProtocolTCP,
+				Port:     int32(port),
+			}},
+		},
+	}
+}
+
+func metricsEndpointsForDB(ctx context.Context, owner client.Object, db *godo.Database) (*corev1.Endpoints, error) {
+	var (
+		subsets      []corev1.EndpointSubset
+		subsetByPort = make(map[int]int)
+	)
+	for _, endpoint := range db.MetricsEndpoints {
+		ips := []string{endpoint.Host}
+		if net.ParseIP(endpoint.Host) == nil {
+			var err error
+			ips, err = lookupMetricsHost(ctx, endpoint.Host)
+			if err != nil {
+				return nil, fmt.Errorf("resolving metrics endpoint %s: %w", endpoint.Host, err)
+			}
+		}
+
+		i, ok := subsetByPort[endpoint.Port]
+		if !ok {
+			i = len(subsets)
+			subsetByPort[endpoint.Port] = i
+			subsets = append(subsets, corev1.EndpointSubset{
+				Ports: []corev1.EndpointPort{{
+					Name:     metricsPortName,
+					Protocol: corev1.ProtocolTCP,
+					Port:     int32(endpoint.Port),
+				}},
+			})
+		}
+		for _, ip := range ips {
+			subsets[i].Addresses = append(subsets[i].Addresses, corev1.EndpointAddress{IP: ip})
+		}
+	}
+
+	return &corev1.Endpoints{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Endpoints",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: owner.GetNamespace(),
+			Name:      owner.GetName() + metricsScrapeTargetSuffix,
+		},
+		Subsets: subsets,
+	}, nil
+}
+
+func metricsServiceMonitorForDB(owner client.Object, db *godo.Database) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"selector": map[string]any{
+				"matchLabels": map[string]any{
+					metricsTargetLabel: owner.GetName(),
+				},
+			},
+			"endpoints": []any{
+				map[string]any{
+					"port":      metricsPortName,
+					"scheme":    "https",
+					"basicAuth": metricsBasicAuth(owner),
+					// The Endpoints are addressed by IP, so the server name
+					// is needed to verify the endpoint's certificate.
+					"tlsConfig": metricsTLSConfig(owner, db.MetricsEndpoints[0].Host),
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(serviceMonitorGVK)
+	obj.SetNamespace(owner.GetNamespace())
+	obj.SetName(owner.GetName() + metricsScrapeTargetSuffix)
+
+	return obj
+}
+
+func metricsBasicAuth(owner client.Object) map[string]any {
+	secretName := owner.GetName() + metricsCredentialsSecretSuffix
+	return map[string]any{
+		"username": map[string]any{"name": secretName, "key": metricsUsernameKey},
+		"password": map[string]any{"name": secretName, "key": metricsPasswordKey},
+	}
+}
+
+func metricsTLSConfig(owner client.Object, serverName string) map[string]any {
+	tlsConfig := map[string]any{
+		"ca": map[string]any{
+			"secret": map[string]any{
+				"name": owner.GetName() + metricsCredentialsSecretSuffix,
+				"key":  metricsCAKey,
+			},
+		},
+	}
+	if serverName != "" {
+		tlsConfig["serverName"] = serverName
+	}
+	return tlsConfig
+}
+
+// generateMetricsPassword returns a random password for the metrics endpoints.
+func generateMetricsPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
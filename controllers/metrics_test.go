@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/extgodo"
+)
+
+var _ = Describe("Metrics", func() {
+	It("should count DigitalOcean API requests by route", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"users": []}`))
+		}))
+		defer server.Close()
+		instrumented, err := godo.New(&http.Client{
+			Transport: extgodo.InstrumentTransport(http.DefaultTransport),
+		}, godo.SetBaseURL(server.URL))
+		Expect(err).NotTo(HaveOccurred())
+
+		labels := map[string]string{"method": "GET", "route": "/v2/databases/{id}/users", "code": "200"}
+		before, _ := gatherValue(metrics.Registry, "do_operator_api_requests_total", labels)
+		_, _, err = instrumented.Databases.ListUsers(ctx, "3d1d7bb5-bf61-4f7f-8d0b-48b1b7ab3d9e", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		after, ok := gatherValue(metrics.Registry, "do_operator_api_requests_total", labels)
+		Expect(ok).To(BeTrue())
+		Expect(after - before).To(BeEquivalentTo(1))
+	})
+
+	It("should report objects by phase and clusters by status", func() {
+		registry := prometheus.NewRegistry()
+		Expect(registry.Register(NewObjectsCollector(k8sClient))).To(Succeed())
+
+		dbCluster := &v1alpha1.DatabaseCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "metrics-db",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.DatabaseClusterSpec{
+				Engine:   "mongodb",
+				Name:     "metrics-db",
+				Version:  "5.0",
+				NumNodes: 1,
+				Size:     "size-slug",
+				Region:   "dev1",
+			},
+		}
+		Expect(k8sClient.Create(ctx, dbCluster)).To(Succeed())
+		defer func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, dbCluster))).To(Succeed())
+		}()
+
+		Eventually(func(g Gomega) {
+			provisioned, ok := gatherValue(registry, "do_operator_objects", map[string]string{
+				"kind": v1alpha1.DatabaseClusterKind, "phase": phaseProvisioned,
+			})
+			g.Expect(ok).To(BeTrue())
+			g.Expect(provisioned).To(BeNumerically(">=", 1))
+
+			creating, _ := gatherValue(registry, "do_operator_database_clusters", map[string]string{
+				"kind": v1alpha1.DatabaseClusterKind, "status": "creating",
+			})
+			online, _ := gatherValue(registry, "do_operator_database_clusters", map[string]string{
+				"kind": v1alpha1.DatabaseClusterKind, "status": "online",
+			})
+			g.Expect(creating + online).To(BeNumerically(">=", 1))
+		}, timeout, interval).Should(Succeed())
+
+		created, ok := gatherValue(metrics.Registry, "do_operator_database_cluster_operations_total", map[string]string{
+			"operation": "create", "result": operationSuccess,
+		})
+		Expect(ok).To(BeTrue())
+		Expect(created).To(BeNumerically(">=", 1))
+	})
+})
+
+// gatherValue returns the value of the counter or gauge with the given name
+// and labels from registry.
+func gatherValue(registry prometheus.Gatherer, name string, labels map[string]string) (float64, bool) {
+	families, err := registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue metrics
+				}
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue(), true
+			}
+			return metric.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
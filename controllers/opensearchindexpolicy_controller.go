@@ -0,0 +1,252 @@
+/*
+Copyright 2022 DigitalOcean.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/do-operator/apitoken"
+	"github.com/digitalocean/godo"
+	"github.com/google/go-cmp/cmp"
+)
+
+const defaultIndexPolicyInterval = time.Hour
+
+// OpenSearchIndexPolicyReconciler reconciles a OpenSearchIndexPolicy object
+type OpenSearchIndexPolicyReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	GodoClients *apitoken.ClientCache
+}
+
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=opensearchindexpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=opensearchindexpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=databases.digitalocean.com,resources=opensearchindexpolicies/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *OpenSearchIndexPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
+	ll := log.FromContext(ctx)
+	ll.Info("reconciling OpenSearchIndexPolicy", "name", req.Name)
+
+	var policy v1alpha1.OpenSearchIndexPolicy
+	err := r.Get(ctx, req.NamespacedName, &policy)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to get OpenSearchIndexPolicy %s: %s", req.NamespacedName, err)
+	}
+
+	// Policies don't own anything in the API, so there's nothing to clean up
+	// when they're deleted.
+	if !policy.DeletionTimestamp.IsZero() {
+		return result, nil
+	}
+
+	originalPolicy := policy.DeepCopy()
+
+	defer func() {
+		if diff := cmp.Diff(policy.Status, originalPolicy.Status); diff != "" {
+			ll.WithValues("diff", diff).Info("status diff detected")
+
+			if err := r.Status().Patch(ctx, &policy, client.MergeFrom(originalPolicy)); err != nil {
+				retErr = utilerror.NewAggregate([]error{retErr, fmt.Errorf("failed to update OpenSearchIndexPolicy status: %s", err)})
+				return
+			}
+			ll.Info("OpenSearchIndexPolicy update succeeded")
+		} else {
+			ll.Info("no OpenSearchIndexPolicy update necessary")
+		}
+	}()
+
+	godoClient, err := r.GodoClients.ClientFor(ctx, policy.Namespace, policy.Spec.ProviderConfigRef)
+	if err != nil {
+		ll.Error(err, "unable to get DigitalOcean client")
+		return result, fmt.Errorf("getting DigitalOcean client: %w", err)
+	}
+	defer requeueWhenThrottled(ctx, godoClient, &result, &retErr)
+
+	// If we haven't noted the cluster's UUID yet, look it up. Subsequent
+	// reconciles won't have to do this.
+	if policy.Status.ClusterUUID == "" {
+		cluster, err := getReferencedCluster(ctx, r.Client, policy.Namespace, policy.Spec.Cluster)
+		if err != nil {
+			return result, err
+		}
+
+		// Indexes can't be listed until the cluster is online, which
+		// triggers another reconcile.
+		if !cluster.isReady() {
+			ll.Info("database is still creating; waiting to evaluate policy")
+			return ctrl.Result{RequeueAfter: clusterNotReadyRequeueTime}, nil
+		}
+
+		policy.Status.ClusterUUID = cluster.UUID
+	}
+
+	ll.Info("evaluating OpenSearchIndexPolicy")
+	return r.reconcilePolicy(ctx, godoClient, &policy)
+}
+
+func (r *OpenSearchIndexPolicyReconciler) reconcilePolicy(ctx context.Context, godoClient *godo.Client, policy *v1alpha1.OpenSearchIndexPolicy) (ctrl.Result, error) {
+	var (
+		clusterUUID = policy.Status.ClusterUUID
+		ll          = log.FromContext(ctx).WithValues(
+			"cluster_uuid", clusterUUID,
+			"dry_run", policy.Spec.DryRun,
+		)
+		now = time.Now()
+	)
+
+	indexes, _, err := godoClient.Databases.ListIndexes(ctx, clusterUUID, nil)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing indexes: %w", err)
+	}
+
+	outside := indexesOutsidePolicy(policy.Spec.Rules, indexes, now)
+	var (
+		deleted []string
+		errs    []error
+	)
+	if !policy.Spec.DryRun {
+		for _, name := range outside {
+			ll.Info("deleting index", "index_name", name)
+			resp, err := godoClient.Databases.DeleteIndex(ctx, clusterUUID, name)
+			if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+				ll.Error(err, "unable to delete index", "index_name", name)
+				errs = append(errs, fmt.Errorf("deleting index %s: %w", name, err))
+				continue
+			}
+			deleted = append(deleted, name)
+		}
+	} else if len(outside) > 0 {
+		ll.Info("dry run; not deleting indexes outside of policy", "index_names", outside)
+	}
+
+	policy.Status.LastEvaluatedAt = &metav1.Time{Time: now}
+	policy.Status.IndexesOutsidePolicy = outside
+	policy.Status.DeletedIndexes = deleted
+
+	if len(errs) > 0 {
+		return ctrl.Result{}, utilerror.NewAggregate(errs)
+	}
+
+	interval := defaultIndexPolicyInterval
+	if policy.Spec.Interval != nil {
+		interval = policy.Spec.Interval.Duration
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// indexesOutsidePolicy returns the sorted names of the indexes that fall
+// outside of at least one of the rules.
+func indexesOutsidePolicy(rules []v1alpha1.IndexRetentionRule, indexes []godo.DatabaseIndex, now time.Time) []string {
+	outside := make(map[string]bool)
+
+	for _, rule := range rules {
+		var matching []godo.DatabaseIndex
+		for _, index := range indexes {
+			if indexMatchesPattern(rule.Pattern, index.IndexName) {
+				matching = append(matching, index)
+			}
+		}
+
+		// Consider the newest indexes first, so that the oldest ones are the
+		// ones that end up exceeding the size limit.
+		sort.SliceStable(matching, func(i, j int) bool {
+			return indexCreateTime(matching[i]).After(indexCreateTime(matching[j]))
+		})
+
+		var totalSize int64
+		for _, index := range matching {
+			totalSize += index.Size
+			if rule.MaxAge != nil {
+				created := indexCreateTime(index)
+				if !created.IsZero() && now.Sub(created) > rule.MaxAge.Duration {
+					outside[index.IndexName] = true
+				}
+			}
+			if rule.MaxTotalSize != nil && totalSize > rule.MaxTotalSize.Value() {
+				outside[index.IndexName] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(outside))
+	for name := range outside {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indexMatchesPattern returns true if the index name matches the glob pattern.
+// Hidden and system indexes, whose names start with a dot, are only matched by
+// patterns that explicitly start with a dot.
+func indexMatchesPattern(pattern, name string) bool {
+	if strings.HasPrefix(name, ".") && !strings.HasPrefix(pattern, ".") {
+		return false
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// indexCreateTime returns the index's creation time, or the zero time if it
+// can't be parsed.
+func indexCreateTime(index godo.DatabaseIndex) time.Time {
+	created, err := time.Parse(time.RFC3339, index.CreateTime)
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OpenSearchIndexPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexReferencedCluster(mgr, &v1alpha1.OpenSearchIndexPolicy{}); err != nil {
+		return err
+	}
+	dependents := handler.EnqueueRequestsFromMapFunc(clusterDependents(r.Client, &v1alpha1.OpenSearchIndexPolicyList{}))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// Every evaluation updates the status, so only react to spec changes
+		// to avoid evaluating the policy in a loop. Periodic evaluation is
+		// driven by requeues.
+		For(&v1alpha1.OpenSearchIndexPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha1.DatabaseCluster{}, dependents, clusterChanges).
+		Watches(&v1alpha1.DatabaseClusterReference{}, dependents, clusterChanges).
+		Complete(r)
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("OpenSearchIndexPolicy controller", func() {
+	Context("When reconciling an OpenSearchIndexPolicy", func() {
+		It("should report and then delete indexes outside of the policy", func() {
+			var (
+				dbCluster       = mustCreateDatabaseClusterWithEngine("opensearch")
+				policyLookupKey = types.NamespacedName{
+					Name:      "log-retention",
+					Namespace: "default",
+				}
+				createdPolicy = &v1alpha1.OpenSearchIndexPolicy{}
+				now           = time.Now().UTC()
+			)
+
+			By("creating indexes in the cluster", func() {
+				for _, index := range []godo.DatabaseIndex{
+					{IndexName: "logs-old", CreateTime: now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)},
+					{IndexName: "logs-new", CreateTime: now.Format(time.RFC3339)},
+					{IndexName: "metrics-old", CreateTime: now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)},
+					{IndexName: ".kibana", CreateTime: now.Add(-10 * 24 * time.Hour).Format(time.RFC3339)},
+				} {
+					Expect(fakeDatabasesService.CreateIndex(dbCluster.Status.UUID, index)).To(Succeed())
+				}
+			})
+
+			By("creating an OpenSearchIndexPolicy object in dry-run mode", func() {
+				policy := &v1alpha1.OpenSearchIndexPolicy{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       v1alpha1.OpenSearchIndexPolicyKind,
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      policyLookupKey.Name,
+						Namespace: policyLookupKey.Namespace,
+					},
+					Spec: v1alpha1.OpenSearchIndexPolicySpec{
+						Cluster: corev1.TypedLocalObjectReference{
+							APIGroup: &v1alpha1.GroupVersion.Group,
+							Kind:     v1alpha1.DatabaseClusterKind,
+							Name:     dbCluster.Name,
+						},
+						Rules: []v1alpha1.IndexRetentionRule{{
+							Pattern: "*",
+							MaxAge:  &metav1.Duration{Duration: 7 * 24 * time.Hour},
+						}},
+						DryRun: true,
+					},
+				}
+				Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+			})
+
+			By("ensuring the indexes outside of the policy are reported but kept", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, policyLookupKey, createdPolicy)).To(Succeed())
+					g.Expect(createdPolicy.Status.LastEvaluatedAt).NotTo(BeNil())
+					g.Expect(createdPolicy.Status.IndexesOutsidePolicy).To(Equal([]string{"logs-old", "metrics-old"}))
+					g.Expect(createdPolicy.Status.DeletedIndexes).To(BeEmpty())
+				}, timeout, interval).Should(Succeed())
+
+				indexes, _, err := fakeDatabasesService.ListIndexes(ctx, dbCluster.Status.UUID, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(indexes).To(HaveLen(4))
+			})
+
+			By("disabling dry-run mode", func() {
+				updatedPolicy := createdPolicy.DeepCopy()
+				updatedPolicy.Spec.DryRun = false
+				Expect(k8sClient.Patch(ctx, updatedPolicy, client.MergeFrom(createdPolicy))).To(Succeed())
+			})
+
+			By("ensuring the indexes outside of the policy are deleted", func() {
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, policyLookupKey, createdPolicy)).To(Succeed())
+					g.Expect(createdPolicy.Status.DeletedIndexes).To(Equal([]string{"logs-old", "metrics-old"}))
+
+					indexes, _, err := fakeDatabasesService.ListIndexes(ctx, dbCluster.Status.UUID, nil)
+					g.Expect(err).NotTo(HaveOccurred())
+					var names []string
+					for _, index := range indexes {
+						names = append(names, index.IndexName)
+					}
+					g.Expect(names).To(ConsistOf("logs-new", ".kibana"))
+				}, timeout, interval).Should(Succeed())
+			})
+		})
+	})
+
+	Context("When evaluating index retention rules", func() {
+		var (
+			now     = time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+			daysAgo = func(days int) string {
+				return now.Add(-time.Duration(days) * 24 * time.Hour).Format(time.RFC3339)
+			}
+			indexes = []godo.DatabaseIndex{
+				{IndexName: "logs-2024.01.09", Size: 300, CreateTime: daysAgo(1)},
+				{IndexName: "logs-2024.01.08", Size: 300, CreateTime: daysAgo(2)},
+				{IndexName: "logs-2024.01.07", Size: 300, CreateTime: daysAgo(3)},
+				{IndexName: "audit-2024.01.01", Size: 10, CreateTime: daysAgo(9)},
+			}
+		)
+
+		It("should delete the oldest indexes once the total size is exceeded", func() {
+			maxSize := resource.MustParse("700")
+			outside := indexesOutsidePolicy([]v1alpha1.IndexRetentionRule{{
+				Pattern:      "logs-*",
+				MaxTotalSize: &maxSize,
+			}}, indexes, now)
+			Expect(outside).To(Equal([]string{"logs-2024.01.07"}))
+		})
+
+		It("should combine the results of multiple rules", func() {
+			outside := indexesOutsidePolicy([]v1alpha1.IndexRetentionRule{{
+				Pattern: "logs-*",
+				MaxAge:  &metav1.Duration{Duration: 36 * time.Hour},
+			}, {
+				Pattern: "audit-*",
+				MaxAge:  &metav1.Duration{Duration: 30 * 24 * time.Hour},
+			}}, indexes, now)
+			Expect(outside).To(Equal([]string{"logs-2024.01.07", "logs-2024.01.08"}))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+const (
+	connectionSuffix         = "-connection"
+	privateConnectionSuffix  = "-private-connection"
+	defaultCredentialsSuffix = "-default-credentials"
+	credentialsSuffix        = "-credentials"
+)
+
+// outputName returns the name of a generated object: the configured name if
+// there is one, and otherwise the owner's name with the default suffix.
+func outputName(configured string, owner client.Object, suffix string) string {
+	if configured != "" {
+		return configured
+	}
+	return owner.GetName() + suffix
+}
+
+// setOutputMetadata adds the configured labels and annotations to a generated
+// object, along with the managed-by label.
+func setOutputMetadata(obj client.Object, md *v1alpha1.OutputMetadata) {
+	if md != nil {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		for k, v := range md.Labels {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+
+		annotations := obj.GetAnnotations()
+		if annotations == nil && len(md.Annotations) > 0 {
+			annotations = make(map[string]string)
+		}
+		for k, v := range md.Annotations {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+	}
+	setManagedByLabel(obj)
+}
+
+// setManagedByLabel sets the managed-by label on a generated object.
+func setManagedByLabel(obj client.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[v1alpha1.ManagedByLabel] = v1alpha1.ManagedByValue
+	obj.SetLabels(labels)
+}
+
+// generatedOutput is a generated object whose name is configurable.
+type generatedOutput struct {
+	obj client.Object
+	// recorded is the status field recording the object's name.
+	recorded *string
+	// defaultName is the name the object had before names were configurable.
+	defaultName string
+}
+
+// newGeneratedOutput returns a generatedOutput for obj, whose default name is
+// the owner's name with the given suffix.
+func newGeneratedOutput(obj client.Object, recorded *string, owner client.Object, suffix string) generatedOutput {
+	return generatedOutput{
+		obj:         obj,
+		recorded:    recorded,
+		defaultName: owner.GetName() + suffix,
+	}
+}
+
+// record records the name of a generated object once it has been applied. If
+// the object was previously generated under a different name, the old object
+// is deleted. When no name was recorded yet, the object is assumed to have had
+// its default name. The old object is looked up with apiReader when it isn't
+// cached, since it may predate the managed-by label.
+func (o generatedOutput) record(ctx context.Context, c client.Client, apiReader client.Reader, owner client.Object) error {
+	oldName := *o.recorded
+	if oldName == "" {
+		oldName = o.defaultName
+	}
+	if oldName != o.obj.GetName() {
+		old := o.obj.DeepCopyObject().(client.Object)
+		err := getGenerated(ctx, c, apiReader, client.ObjectKey{Namespace: o.obj.GetNamespace(), Name: oldName}, old)
+		switch {
+		case kerrors.IsNotFound(err):
+		case err != nil:
+			return fmt.Errorf("getting renamed object %s/%s: %w", o.obj.GetNamespace(), oldName, err)
+		case metav1.IsControlledBy(old, owner):
+			// Only delete objects we created, in case the old name has since
+			// been taken by something else.
+			if err := c.Delete(ctx, old); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("deleting renamed object %s: %w", client.ObjectKeyFromObject(old), err)
+			}
+		}
+	}
+	*o.recorded = o.obj.GetName()
+	return nil
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/digitalocean/do-operator/apitoken"
+)
+
+// releaseWithoutCredentials removes the finalizer of obj, which is being
+// deleted, if err from resolving its godo client means its provider config or
+// the config's token Secret is gone. That's a normal order of deletion when a
+// namespace is torn down, and without credentials obj's DigitalOcean resources
+// can't be cleaned up, so they're left in place with a warning event rather
+// than keeping obj from being deleted. It returns whether the finalizer was
+// removed.
+func releaseWithoutCredentials(ctx context.Context, recorder events.EventRecorder, obj client.Object, err error) bool {
+	if !errors.Is(err, apitoken.ErrProviderConfigNotFound) && !errors.Is(err, apitoken.ErrCredentialsNotFound) {
+		return false
+	}
+	log.FromContext(ctx).Error(err, "DigitalOcean credentials are gone; leaving DigitalOcean resources in place")
+	recorder.Eventf(obj, nil, corev1.EventTypeWarning, "CleanupSkipped", "Delete",
+		"DigitalOcean resources were left in place because the DigitalOcean credentials are gone: %v", err)
+	controllerutil.RemoveFinalizer(obj, finalizerName)
+	return true
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/digitalocean/do-operator/api/v1alpha1"
+)
+
+var _ = Describe("DigitalOceanProviderConfig", func() {
+	mustCreateProviderConfig := func(namespace, name string) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-token",
+				Namespace: namespace,
+			},
+			StringData: map[string]string{
+				"token": name + "-token",
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		providerConfig := &v1alpha1.DigitalOceanProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.DigitalOceanProviderConfigSpec{
+				CredentialsSecretRef: v1alpha1.ProviderCredentialsSecretReference{
+					Name: secret.Name,
+					Key:  "token",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, providerConfig)).To(Succeed())
+		// Wait for the controllers' cache to see the provider config, so that
+		// objects created next don't fall back to the default client.
+		Eventually(func() error {
+			return k8sManager.GetClient().Get(ctx, client.ObjectKeyFromObject(providerConfig), &v1alpha1.DigitalOceanProviderConfig{})
+		}, timeout, interval).Should(Succeed())
+	}
+
+	newDBCluster := func(namespace, name string, providerConfigRef *corev1.LocalObjectReference) *v1alpha1.DatabaseCluster {
+		return &v1alpha1.DatabaseCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.DatabaseClusterSpec{
+				Engine:            "mysql",
+				Name:              name,
+				Version:           "8",
+				NumNodes:          1,
+				Size:              "size-slug",
+				Region:            "dev1",
+				ProviderConfigRef: providerConfigRef,
+			},
+		}
+	}
+
+	// expectCreatedInTeam waits for the cluster to be created and checks that
+	// it was created with the provider config's client.
+	expectCreatedInTeam := func(cluster *v1alpha1.DatabaseCluster) {
+		created := &v1alpha1.DatabaseCluster{}
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, created)).To(Succeed())
+			g.Expect(created.Status.UUID).NotTo(BeEmpty())
+		}, timeout, interval).Should(Succeed())
+
+		_, _, err := teamDatabasesService.Get(ctx, created.Status.UUID)
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = fakeDatabasesService.Get(ctx, created.Status.UUID)
+		Expect(err).To(HaveOccurred())
+	}
+
+	Context("When an object names a provider config", func() {
+		It("should use the provider config's client", func() {
+			mustCreateProviderConfig("default", "team-b")
+
+			cluster := newDBCluster("default", "team-b-db", &corev1.LocalObjectReference{Name: "team-b"})
+			Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+			expectCreatedInTeam(cluster)
+		})
+
+		It("should not create anything when the provider config doesn't exist", func() {
+			cluster := newDBCluster("default", "no-team-db", &corev1.LocalObjectReference{Name: "no-such-team"})
+			Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+			Consistently(func(g Gomega) {
+				created := &v1alpha1.DatabaseCluster{}
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "no-team-db"}, created)).To(Succeed())
+				g.Expect(created.Status.UUID).To(BeEmpty())
+			}, 5*time.Second, interval).Should(Succeed())
+		})
+	})
+
+	Context("When a provider config is deleted before the objects using it", func() {
+		It("should let the objects be deleted", func() {
+			mustCreateProviderConfig("default", "team-d")
+
+			cluster := newDBCluster("default", "team-d-db", &corev1.LocalObjectReference{Name: "team-d"})
+			Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+			expectCreatedInTeam(cluster)
+
+			Expect(k8sClient.Delete(ctx, &v1alpha1.DigitalOceanProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-d", Namespace: "default"},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-d-token", Namespace: "default"},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), &v1alpha1.DatabaseCluster{})
+				return kerrors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
+	Context("When a namespace has a default provider config", func() {
+		It("should use it for objects that don't name a provider config", func() {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "team-c",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			mustCreateProviderConfig(ns.Name, v1alpha1.DefaultProviderConfigName)
+
+			cluster := newDBCluster(ns.Name, "team-c-db", nil)
+			Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+
+			expectCreatedInTeam(cluster)
+		})
+	})
+})
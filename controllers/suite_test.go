@@ -24,16 +24,22 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	databasesv1beta1 "github.com/digitalocean/do-operator/api/v1beta1"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/do-operator/fakegodo"
 	"github.com/digitalocean/godo"
 	//+kubebuilder:scaffold:imports
@@ -55,6 +61,15 @@ var (
 	cancel               context.CancelFunc
 	k8sManager           manager.Manager
 	fakeDatabasesService = &fakegodo.FakeDatabasesService{}
+	// teamDatabasesService backs the godo clients built for
+	// DigitalOceanProviderConfigs.
+	teamDatabasesService = &fakegodo.FakeDatabasesService{}
+	godoClients          *apitoken.ClientCache
+	apiTokenSource       = apitoken.NewSource("")
+	apiTokenSecretKey    = types.NamespacedName{
+		Name:      "do-api-token",
+		Namespace: "default",
+	}
 )
 
 func TestAPIs(t *testing.T) {
@@ -66,65 +81,142 @@ func TestAPIs(t *testing.T) {
 var _ = BeforeSuite(func() {
 	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
 
+	// Both versions must be in the scheme before the environment starts, so
+	// that the CRDs are installed with the manager's conversion webhook.
+	err := databasesv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = databasesv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	//+kubebuilder:scaffold:scheme
+
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: true,
 	}
 
-	var err error
 	// cfg is defined in this file globally.
 	cfg, err = testEnv.Start()
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cfg).NotTo(BeNil())
 
-	err = databasesv1alpha1.AddToScheme(scheme.Scheme)
-	Expect(err).NotTo(HaveOccurred())
-
-	//+kubebuilder:scaffold:scheme
-
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
 	k8sManager, err = ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme.Scheme,
+		Cache: cache.Options{
+			ByObject: GeneratedObjectsOnly(),
+		},
+		WebhookServer: &webhook.DefaultServer{
+			Options: webhook.Options{
+				Host:    webhookInstallOptions.LocalServingHost,
+				Port:    webhookInstallOptions.LocalServingPort,
+				CertDir: webhookInstallOptions.LocalServingCertDir,
+			},
+		},
 	})
 	Expect(err).ToNot(HaveOccurred())
 
+	// v1alpha1 objects are stored as v1beta1, so the API server calls the
+	// conversion webhook to read and write them.
+	k8sManager.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(k8sManager.GetScheme(), k8sManager.GetConverterRegistry()))
+
+	metadata, err := AddMetadataCache(k8sManager, nil)
+	Expect(err).ToNot(HaveOccurred())
+
+	godoClients = apitoken.NewClientCache(k8sManager.GetClient(), metadata, k8sManager.GetAPIReader(), &godo.Client{
+		Databases: fakeDatabasesService,
+		Tags:      &fakegodo.FakeTagsService{Databases: fakeDatabasesService},
+	}, func(*apitoken.Source) (*godo.Client, error) {
+		return &godo.Client{
+			Databases: teamDatabasesService,
+			Tags:      &fakegodo.FakeTagsService{Databases: teamDatabasesService},
+		}, nil
+	})
+
+	metricsRotations := NewMetricsCredentialsRotations()
 	err = (&DatabaseClusterReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-		GodoClient: &godo.Client{
-			Databases: fakeDatabasesService,
-		},
+		Client:           k8sManager.GetClient(),
+		APIReader:        k8sManager.GetAPIReader(),
+		Scheme:           k8sManager.GetScheme(),
+		GodoClients:      godoClients,
+		Recorder:         k8sManager.GetEventRecorder("databasecluster-controller"),
+		MetricsRotations: metricsRotations,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&DatabaseClusterReferenceReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-		GodoClient: &godo.Client{
-			Databases: fakeDatabasesService,
-		},
+		Client:           k8sManager.GetClient(),
+		APIReader:        k8sManager.GetAPIReader(),
+		Scheme:           k8sManager.GetScheme(),
+		GodoClients:      godoClients,
+		Recorder:         k8sManager.GetEventRecorder("databaseclusterreference-controller"),
+		MetricsRotations: metricsRotations,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&DatabaseUserReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-		GodoClient: &godo.Client{
-			Databases: fakeDatabasesService,
-		},
+		Client:      k8sManager.GetClient(),
+		APIReader:   k8sManager.GetAPIReader(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    k8sManager.GetEventRecorder("databaseuser-controller"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&DatabaseUserReferenceReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
-		GodoClient: &godo.Client{
-			Databases: fakeDatabasesService,
-		},
+		Client:      k8sManager.GetClient(),
+		APIReader:   k8sManager.GetAPIReader(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&KafkaSchemaSubjectReconciler{
+		Client:      k8sManager.GetClient(),
+		APIReader:   k8sManager.GetAPIReader(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    k8sManager.GetEventRecorder("kafkaschemasubject-controller"),
+		Metadata:    metadata,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&DatabaseLogsinkReconciler{
+		Client:      k8sManager.GetClient(),
+		APIReader:   k8sManager.GetAPIReader(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    k8sManager.GetEventRecorder("databaselogsink-controller"),
+		Metadata:    metadata,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&DatabaseOnlineMigrationReconciler{
+		Client:      k8sManager.GetClient(),
+		APIReader:   k8sManager.GetAPIReader(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    k8sManager.GetEventRecorder("databaseonlinemigration-controller"),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&OpenSearchIndexPolicyReconciler{
+		Client:      k8sManager.GetClient(),
+		Scheme:      k8sManager.GetScheme(),
+		GodoClients: godoClients,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&APITokenSecretReconciler{
+		SecretKey: apiTokenSecretKey,
+		DataKey:   "access-token",
+		Source:    apiTokenSource,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
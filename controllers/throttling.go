@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+	utilerror "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/digitalocean/do-operator/extgodo"
+)
+
+// requeueWhenThrottled replaces a reconcile error caused by the DigitalOcean
+// API rate limit with a requeue at the time the limit resets, if the godo
+// client is being throttled. Throttling is expected with many objects, so it
+// shouldn't put them into exponential backoff. Other errors are returned
+// unchanged. Reconcilers defer it once they have a godo client.
+func requeueWhenThrottled(ctx context.Context, godoClient *godo.Client, result *ctrl.Result, err *error) {
+	if *err == nil || !isThrottlingError(*err) {
+		return
+	}
+	until, ok := extgodo.ThrottledUntil(godoClient)
+	if !ok {
+		return
+	}
+	log.FromContext(ctx).Info("DigitalOcean API rate limit reached; requeueing", "until", until, "error", (*err).Error())
+	*result = ctrl.Result{RequeueAfter: time.Until(until)}
+	*err = nil
+}
+
+// isThrottlingError returns whether err, or every error it aggregates, is a
+// request refused by the rate limiter or a 429 response from the API.
+func isThrottlingError(err error) bool {
+	var agg utilerror.Aggregate
+	if errors.As(err, &agg) {
+		for _, err := range agg.Errors() {
+			if !isThrottlingError(err) {
+				return false
+			}
+		}
+		return len(agg.Errors()) > 0
+	}
+
+	var throttledErr *extgodo.ThrottledError
+	if errors.As(err, &throttledErr) {
+		return true
+	}
+	var respErr *godo.ErrorResponse
+	return errors.As(err, &respErr) && respErr.Response != nil &&
+		respErr.Response.StatusCode == http.StatusTooManyRequests
+}
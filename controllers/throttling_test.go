@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/digitalocean/godo"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/digitalocean/do-operator/extgodo"
+)
+
+var _ = Describe("DigitalOcean API throttling", func() {
+	var (
+		requests  atomic.Int32
+		respond   func(w http.ResponseWriter, attempt int32)
+		server    *httptest.Server
+		rlClient  *godo.Client
+		okDBsJSON = `{"databases": []}`
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respond(w, requests.Add(1))
+		}))
+		var err error
+		rlClient, err = godo.New(&http.Client{
+			Transport: extgodo.NewRateLimiter().Transport(http.DefaultTransport),
+		}, godo.SetBaseURL(server.URL))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should retry a GET when the limit resets soon", func() {
+		respond = func(w http.ResponseWriter, attempt int32) {
+			if attempt == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(okDBsJSON))
+		}
+
+		_, _, err := rlClient.Databases.List(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should requeue at the reset time instead of returning an error", func() {
+		reset := time.Now().Add(10 * time.Minute)
+		respond = func(w http.ResponseWriter, attempt int32) {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+
+		_, _, err := rlClient.Databases.List(ctx, nil)
+		Expect(err).To(HaveOccurred())
+
+		By("not sending further requests until the reset", func() {
+			_, _, err := rlClient.Databases.Create(ctx, &godo.DatabaseCreateRequest{})
+			var throttledErr *extgodo.ThrottledError
+			Expect(errors.As(err, &throttledErr)).To(BeTrue())
+			Expect(requests.Load()).To(BeEquivalentTo(1))
+		})
+
+		By("replacing the reconcile error with a requeue", func() {
+			var result ctrl.Result
+			requeueWhenThrottled(ctx, rlClient, &result, &err)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Minute, 5*time.Second))
+		})
+	})
+
+	It("should only requeue errors caused by the rate limit", func() {
+		respond = func(w http.ResponseWriter, attempt int32) {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+
+		_, _, throttledErr := rlClient.Databases.List(ctx, nil)
+		Expect(throttledErr).To(HaveOccurred())
+
+		By("requeueing a wrapped rate limit error", func() {
+			err := fmt.Errorf("listing databases: %w", throttledErr)
+			var result ctrl.Result
+			requeueWhenThrottled(ctx, rlClient, &result, &err)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		})
+
+		By("returning unrelated errors while throttled", func() {
+			err := errors.New("failed to get DatabaseCluster")
+			var result ctrl.Result
+			requeueWhenThrottled(ctx, rlClient, &result, &err)
+			Expect(err).To(MatchError("failed to get DatabaseCluster"))
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+	})
+
+	It("should leave other errors alone", func() {
+		respond = func(w http.ResponseWriter, attempt int32) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		_, _, err := rlClient.Databases.List(ctx, nil)
+		Expect(err).To(HaveOccurred())
+
+		var result ctrl.Result
+		requeueWhenThrottled(ctx, rlClient, &result, &err)
+		Expect(err).To(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+	})
+})
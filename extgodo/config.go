@@ -0,0 +1,108 @@
+package extgodo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/digitalocean/godo"
+)
+
+// DecodeDatabaseConfig decodes configuration options, as a JSON object keyed by
+// the options' API names, into the engine's config type, such as
+// *godo.MySQLConfig. Unknown options are rejected.
+func DecodeDatabaseConfig(engine string, options []byte) (any, error) {
+	var config any
+	switch engine {
+	case "mysql":
+		config = &godo.MySQLConfig{}
+	case "pg":
+		config = &godo.PostgreSQLConfig{}
+	case "redis":
+		config = &godo.RedisConfig{}
+	case "valkey":
+		config = &godo.ValkeyConfig{}
+	case "mongodb":
+		config = &godo.MongoDBConfig{}
+	case "kafka":
+		config = &godo.KafkaConfig{}
+	case "opensearch":
+		config = &godo.OpensearchConfig{}
+	default:
+		return nil, fmt.Errorf("engine %q has no configuration options", engine)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(options))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// GetDatabaseConfig returns a database cluster's configuration options, keyed
+// by their API names.
+func GetDatabaseConfig(ctx context.Context, c *godo.Client, engine, dbUUID string) (map[string]json.RawMessage, error) {
+	var (
+		config any
+		err    error
+	)
+	switch engine {
+	case "mysql":
+		config, _, err = c.Databases.GetMySQLConfig(ctx, dbUUID)
+	case "pg":
+		config, _, err = c.Databases.GetPostgreSQLConfig(ctx, dbUUID)
+	case "redis":
+		config, _, err = c.Databases.GetRedisConfig(ctx, dbUUID)
+	case "valkey":
+		config, _, err = c.Databases.GetValkeyConfig(ctx, dbUUID)
+	case "mongodb":
+		config, _, err = c.Databases.GetMongoDBConfig(ctx, dbUUID)
+	case "kafka":
+		config, _, err = c.Databases.GetKafkaConfig(ctx, dbUUID)
+	case "opensearch":
+		config, _, err = c.Databases.GetOpensearchConfig(ctx, dbUUID)
+	default:
+		return nil, fmt.Errorf("engine %q has no configuration options", engine)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var options map[string]json.RawMessage
+	if err := json.Unmarshal(b, &options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// UpdateDatabaseConfig updates a database cluster's configuration with the
+// options set in config, which must be a config returned by
+// DecodeDatabaseConfig.
+func UpdateDatabaseConfig(ctx context.Context, c *godo.Client, dbUUID string, config any) error {
+	var err error
+	switch config := config.(type) {
+	case *godo.MySQLConfig:
+		_, err = c.Databases.UpdateMySQLConfig(ctx, dbUUID, config)
+	case *godo.PostgreSQLConfig:
+		_, err = c.Databases.UpdatePostgreSQLConfig(ctx, dbUUID, config)
+	case *godo.RedisConfig:
+		_, err = c.Databases.UpdateRedisConfig(ctx, dbUUID, config)
+	case *godo.ValkeyConfig:
+		_, err = c.Databases.UpdateValkeyConfig(ctx, dbUUID, config)
+	case *godo.MongoDBConfig:
+		_, err = c.Databases.UpdateMongoDBConfig(ctx, dbUUID, config)
+	case *godo.KafkaConfig:
+		_, err = c.Databases.UpdateKafkaConfig(ctx, dbUUID, config)
+	case *godo.OpensearchConfig:
+		_, err = c.Databases.UpdateOpensearchConfig(ctx, dbUUID, config)
+	default:
+		return fmt.Errorf("unsupported database config type %T", config)
+	}
+	return err
+}
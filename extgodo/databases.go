@@ -12,3 +12,25 @@ type DatabaseValidateCreateRequest struct {
 	godo.DatabaseCreateRequest
 	DryRun bool `json:"dry_run"`
 }
+
+// DatabaseDatadogLogsinkConfig is the configuration for a logsink that ships
+// logs to Datadog.
+// TODO Get rid of this once Datadog logsinks are supported in godo.
+type DatabaseDatadogLogsinkConfig struct {
+	Site          string `json:"site"`
+	DatadogAPIKey string `json:"datadog_api_key"`
+}
+
+// DatabaseCreateDatadogLogsinkRequest is a request to create a Datadog
+// logsink.
+type DatabaseCreateDatadogLogsinkRequest struct {
+	Name   string                        `json:"sink_name"`
+	Type   string                        `json:"sink_type"`
+	Config *DatabaseDatadogLogsinkConfig `json:"config"`
+}
+
+// DatabaseUpdateDatadogLogsinkRequest is a request to update a Datadog
+// logsink.
+type DatabaseUpdateDatadogLogsinkRequest struct {
+	Config *DatabaseDatadogLogsinkConfig `json:"config"`
+}
@@ -0,0 +1,102 @@
+package extgodo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "do_operator_api_requests_total",
+		Help: "Number of requests made to the DigitalOcean API, by method, route and status code.",
+	}, []string{"method", "route", "code"})
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "do_operator_api_request_duration_seconds",
+		Help:    "Latency of requests made to the DigitalOcean API, by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration)
+}
+
+// apiPathWords are the fixed segments of the DigitalOcean API paths used by
+// the operator. Any other segment is an ID or name.
+var apiPathWords = map[string]bool{
+	"v2":               true,
+	"databases":        true,
+	"options":          true,
+	"metrics":          true,
+	"credentials":      true,
+	"ca":               true,
+	"config":           true,
+	"resize":           true,
+	"migrate":          true,
+	"maintenance":      true,
+	"install_update":   true,
+	"backups":          true,
+	"users":            true,
+	"reset_auth":       true,
+	"dbs":              true,
+	"pools":            true,
+	"replicas":         true,
+	"promote":          true,
+	"eviction_policy":  true,
+	"sql_mode":         true,
+	"firewall":         true,
+	"upgrade":          true,
+	"topics":           true,
+	"events":           true,
+	"indexes":          true,
+	"logsink":          true,
+	"online-migration": true,
+	"schema-registry":  true,
+}
+
+// routeTemplate returns the route of an API path with IDs and names replaced,
+// e.g. /v2/databases/{id}/users/{id}, to keep the metrics' cardinality
+// bounded.
+func routeTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if !apiPathWords[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// InstrumentTransport returns an http.RoundTripper that sends requests with
+// base and records the do_operator_api_* metrics for them.
+func InstrumentTransport(base http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{base: base}
+}
+
+type instrumentedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	labels := prometheus.Labels{
+		"method": req.Method,
+		"route":  routeTemplate(req.URL.Path),
+		"code":   code,
+	}
+	apiRequests.With(labels).Inc()
+	apiRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
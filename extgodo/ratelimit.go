@@ -0,0 +1,190 @@
+package extgodo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// The DigitalOcean API allows 250 requests per minute per token. The
+	// bucket's rate plus its burst stays under that in any given minute.
+	requestsPerMinute = 240
+	requestsBurst     = 10
+
+	// maxGetRetries is how many times a throttled GET is retried.
+	maxGetRetries = 3
+	// maxRetryWait is the longest a GET waits for the rate limit to reset
+	// before it's retried. Longer waits are left to the caller.
+	maxRetryWait = 10 * time.Second
+	// defaultRetryAfter is how long to back off after a 429 response that
+	// doesn't say when to retry.
+	defaultRetryAfter = time.Minute
+
+	// These are the headers godo reads into godo.Rate.
+	headerRateRemaining = "RateLimit-Remaining"
+	headerRateReset     = "RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
+)
+
+// ThrottledError is returned for requests that weren't sent because the
+// DigitalOcean API rate limit has been reached.
+type ThrottledError struct {
+	// Until is when the rate limit resets.
+	Until time.Time
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("DigitalOcean API rate limit reached until %s", e.Until.Format(time.RFC3339))
+}
+
+// RateLimiter throttles requests made to the DigitalOcean API with one token.
+// It combines a client-side token bucket with the rate limit state reported by
+// the API, so that everything sharing the limiter backs off together.
+type RateLimiter struct {
+	bucket *rate.Limiter
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+// NewRateLimiter returns a RateLimiter for a single API token.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		bucket: rate.NewLimiter(rate.Every(time.Minute/requestsPerMinute), requestsBurst),
+	}
+}
+
+// ThrottledUntil returns when the rate limit resets, if requests are currently
+// being held back.
+func (l *RateLimiter) ThrottledUntil() (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Now().Before(l.blockedUntil) {
+		return l.blockedUntil, true
+	}
+	return time.Time{}, false
+}
+
+// Transport returns an http.RoundTripper that sends requests with base,
+// subject to the limiter. Throttled GETs are retried if the limit resets soon;
+// other requests made while the limit is exhausted fail with a ThrottledError.
+func (l *RateLimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{limiter: l, base: base}
+}
+
+// observe records the rate limit state reported in a response.
+func (l *RateLimiter) observe(resp *http.Response) {
+	var until time.Time
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		until = retryAfter(resp.Header)
+	case resp.Header.Get(headerRateRemaining) == "0":
+		until = rateReset(resp.Header)
+	}
+	if until.IsZero() {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// retryAfter returns when a throttled request may be retried.
+func retryAfter(h http.Header) time.Time {
+	if v := h.Get(headerRetryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return t
+		}
+	}
+	if h.Get(headerRateRemaining) == "0" {
+		if reset := rateReset(h); !reset.IsZero() {
+			return reset
+		}
+	}
+	return time.Now().Add(defaultRetryAfter)
+}
+
+// rateReset returns when the hourly rate limit resets.
+func rateReset(h http.Header) time.Time {
+	v, _ := strconv.ParseInt(h.Get(headerRateReset), 10, 64)
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(v, 0)
+}
+
+// ThrottledUntil returns when the rate limit resets, if requests made with the
+// godo client are currently being held back. It only reports throttling for
+// clients whose transport was built by a RateLimiter.
+func ThrottledUntil(c *godo.Client) (time.Time, bool) {
+	if c == nil || c.HTTPClient == nil {
+		return time.Time{}, false
+	}
+	t, ok := c.HTTPClient.Transport.(*rateLimitTransport)
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.limiter.ThrottledUntil()
+}
+
+type rateLimitTransport struct {
+	limiter *RateLimiter
+	base    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for retries := 0; ; retries++ {
+		if until, ok := t.limiter.ThrottledUntil(); ok {
+			if !t.shouldWait(req, retries, until) {
+				return nil, &ThrottledError{Until: until}
+			}
+			timer := time.NewTimer(time.Until(until))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := t.limiter.bucket.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.limiter.observe(resp)
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		if until, _ := t.limiter.ThrottledUntil(); !t.shouldWait(req, retries+1, until) {
+			// Let the caller see the 429.
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// shouldWait returns whether a request should wait for the rate limit to reset
+// and then be retried. Only GETs are retried, since they're idempotent.
+func (t *rateLimitTransport) shouldWait(req *http.Request, retries int, until time.Time) bool {
+	return req.Method == http.MethodGet &&
+		retries <= maxGetRetries &&
+		time.Until(until) <= maxRetryWait
+}
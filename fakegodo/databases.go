@@ -1,9 +1,12 @@
 package fakegodo
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -29,12 +32,38 @@ var (
 // * Get returns a previously-created database object with the status "online".
 // * Delete deletes a previously-created database object.
 // * Resize updates a previously-created object with the provided parameters.
+// * The firewall rules, maintenance window and engine config of
+// previously-created databases can be fetched and updated. Databases are
+// created with a Sunday midnight maintenance window.
+// * Users and Kafka schema registry subjects can be created, fetched and
+// deleted in previously-created databases. Users' settings can also be updated
+// and their auth reset. Kafka users get an access certificate and key. Like the
+// real schema registry, Avro and JSON schemas are stored in compact form, and
+// KafkaSchemaRegistrations reports how often a subject's schema was registered.
+// * Logsinks can be created, fetched, listed, updated and deleted in
+// previously-created databases.
+// * Online migrations can be started, fetched and stopped in
+// previously-created databases. Started migrations have the status "syncing".
+// * Indexes can be listed and deleted in previously-created databases. Since
+// godo can't create indexes, CreateIndex is provided for tests to set them up.
+// * Events can be listed for previously-created databases. Since godo can't
+// create events, CreateDatabaseEvent is provided for tests to set them up.
+// * The CA of previously-created databases can be fetched.
+// * Metrics credentials can be fetched and updated.
 type FakeDatabasesService struct {
 	Options *godo.DatabaseOptions
 
-	mu        sync.RWMutex
-	databases []godo.Database
-	users     map[string][]godo.DatabaseUser
+	mu             sync.RWMutex
+	databases      []godo.Database
+	users          map[string][]godo.DatabaseUser
+	schemaSubjects map[string][]fakeSchemaSubject
+	logsinks       map[string][]godo.DatabaseLogsink
+	migrations     map[string]godo.DatabaseOnlineMigrationStatus
+	indexes        map[string][]godo.DatabaseIndex
+	events         map[string][]godo.DatabaseEvent
+	metricsCreds   *godo.DatabaseMetricsCredentials
+	firewallRules  map[string][]godo.DatabaseFirewallRule
+	configs        map[string]map[string]json.RawMessage
 
 	// satisfy interface for unimplemented methods
 	godo.DatabasesService
@@ -85,11 +114,33 @@ func (f *FakeDatabasesService) Create(_ context.Context, req *godo.DatabaseCreat
 			Password: "private-password",
 			SSL:      true,
 		},
+		MetricsEndpoints: []*godo.ServiceAddress{{
+			Host: "192.0.2.10",
+			Port: 9273,
+		}},
+		Tags:           req.Tags,
+		StorageSizeMib: req.StorageSizeMib,
+		MaintenanceWindow: &godo.DatabaseMaintenanceWindow{
+			Day:  "sunday",
+			Hour: "00:00:00",
+		},
 		Status: CreatingStatus,
 	}
 
 	f.mu.Lock()
 	f.databases = append(f.databases, db)
+	if len(req.Rules) > 0 {
+		if f.firewallRules == nil {
+			f.firewallRules = make(map[string][]godo.DatabaseFirewallRule)
+		}
+		for _, rule := range req.Rules {
+			f.firewallRules[db.ID] = append(f.firewallRules[db.ID], godo.DatabaseFirewallRule{
+				ClusterUUID: db.ID,
+				Type:        rule.Type,
+				Value:       rule.Value,
+			})
+		}
+	}
 	f.mu.Unlock()
 
 	return &db, okResponse, nil
@@ -119,6 +170,9 @@ func (f *FakeDatabasesService) Resize(_ context.Context, dbUUID string, req *god
 		if db.ID == dbUUID {
 			db.NumNodes = req.NumNodes
 			db.SizeSlug = req.SizeSlug
+			if req.StorageSizeMib != 0 {
+				db.StorageSizeMib = req.StorageSizeMib
+			}
 			return okResponse, nil
 		}
 	}
@@ -160,9 +214,15 @@ func (f *FakeDatabasesService) CreateUser(_ context.Context, dbUUID string, req
 			}
 
 			u := godo.DatabaseUser{
-				Name:     req.Name,
-				Role:     "normal",
-				Password: rand.String(16),
+				Name:          req.Name,
+				Role:          "normal",
+				Password:      rand.String(16),
+				MySQLSettings: req.MySQLSettings,
+				Settings:      copyUserSettings(req.Settings),
+			}
+			if db.EngineSlug == "kafka" {
+				u.AccessCert = "access-cert-" + req.Name
+				u.AccessKey = "access-key-" + req.Name
 			}
 			f.users[dbUUID] = append(f.users[dbUUID], u)
 			return &u, okResponse, nil
@@ -172,6 +232,90 @@ func (f *FakeDatabasesService) CreateUser(_ context.Context, dbUUID string, req
 	return nil, notFoundResponse, errors.New("not found")
 }
 
+// UpdateUser ...
+func (f *FakeDatabasesService) UpdateUser(_ context.Context, dbUUID string, username string, req *godo.DatabaseUpdateUserRequest) (*godo.DatabaseUser, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.users[dbUUID] {
+		u := &f.users[dbUUID][i]
+		if u.Name == username {
+			u.Settings = copyUserSettings(req.Settings)
+			cpy := *u
+			return &cpy, okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// copyUserSettings deep-copies user settings, assigning IDs to Kafka ACLs as
+// the API does.
+func copyUserSettings(settings *godo.DatabaseUserSettings) *godo.DatabaseUserSettings {
+	if settings == nil {
+		return nil
+	}
+
+	cpy := *settings
+	cpy.ACL = nil
+	for _, acl := range settings.ACL {
+		aclCpy := *acl
+		if aclCpy.ID == "" {
+			aclCpy.ID = uuid.New().String()
+		}
+		cpy.ACL = append(cpy.ACL, &aclCpy)
+	}
+	cpy.OpenSearchACL = nil
+	for _, acl := range settings.OpenSearchACL {
+		aclCpy := *acl
+		cpy.OpenSearchACL = append(cpy.OpenSearchACL, &aclCpy)
+	}
+	if settings.MongoUserSettings != nil {
+		mongoCpy := *settings.MongoUserSettings
+		mongoCpy.Databases = append([]string(nil), settings.MongoUserSettings.Databases...)
+		cpy.MongoUserSettings = &mongoCpy
+	}
+	return &cpy
+}
+
+// ResetUserAuth ...
+func (f *FakeDatabasesService) ResetUserAuth(_ context.Context, dbUUID string, username string, req *godo.DatabaseResetUserAuthRequest) (*godo.DatabaseUser, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.users[dbUUID] {
+		u := &f.users[dbUUID][i]
+		if u.Name == username {
+			u.Password = rand.String(16)
+			if req.MySQLSettings != nil {
+				u.MySQLSettings = req.MySQLSettings
+			}
+			cpy := *u
+			return &cpy, okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// ClearUserPassword stops returning a user's password, as the API does for
+// MongoDB users once they've been created. It isn't part of
+// godo.DatabasesService.
+func (f *FakeDatabasesService) ClearUserPassword(dbUUID, username string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.users[dbUUID] {
+		u := &f.users[dbUUID][i]
+		if u.Name == username {
+			u.Password = ""
+			return nil
+		}
+	}
+
+	return errors.New("not found")
+}
+
 // DeleteUser ...
 func (f *FakeDatabasesService) DeleteUser(_ context.Context, dbUUID string, username string) (*godo.Response, error) {
 	f.mu.Lock()
@@ -196,3 +340,595 @@ func (f *FakeDatabasesService) DeleteUser(_ context.Context, dbUUID string, user
 func (f *FakeDatabasesService) ListOptions(todo context.Context) (*godo.DatabaseOptions, *godo.Response, error) {
 	return f.Options, okResponse, nil
 }
+
+type fakeSchemaSubject struct {
+	godo.DatabaseKafkaSchemaRegistrySubject
+	compatibilityLevel string
+	registrations      int
+}
+
+// hasDatabase returns true if a database with the given UUID exists. The
+// caller must hold f.mu.
+func (f *FakeDatabasesService) hasDatabase(dbUUID string) bool {
+	for _, db := range f.databases {
+		if db.ID == dbUUID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetKafkaSchemaRegistry ...
+func (f *FakeDatabasesService) GetKafkaSchemaRegistry(_ context.Context, dbUUID, subjectName string) (*godo.DatabaseKafkaSchemaRegistrySubject, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, subject := range f.schemaSubjects[dbUUID] {
+		if subject.SubjectName == subjectName {
+			cpy := subject.DatabaseKafkaSchemaRegistrySubject
+			return &cpy, okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// CreateKafkaSchemaRegistry registers a new schema for a subject, creating the
+// subject if it doesn't exist.
+func (f *FakeDatabasesService) CreateKafkaSchemaRegistry(_ context.Context, dbUUID string, req *godo.DatabaseKafkaSchemaRegistryRequest) (*godo.DatabaseKafkaSchemaRegistrySubject, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.schemaSubjects == nil {
+		f.schemaSubjects = make(map[string][]fakeSchemaSubject)
+	}
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	schema := req.Schema
+	if req.SchemaType != "PROTOBUF" {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, []byte(schema)); err == nil {
+			schema = compacted.String()
+		}
+	}
+	registered := godo.DatabaseKafkaSchemaRegistrySubject{
+		SubjectName: req.SubjectName,
+		SchemaType:  req.SchemaType,
+		Schema:      schema,
+		SchemaID:    rand.IntnRange(1, 1<<20),
+	}
+	for i := range f.schemaSubjects[dbUUID] {
+		subject := &f.schemaSubjects[dbUUID][i]
+		if subject.SubjectName == req.SubjectName {
+			subject.registrations++
+			if subject.Schema == registered.Schema && subject.SchemaType == registered.SchemaType {
+				// Registering the latest schema again doesn't create a new
+				// version.
+				registered.SchemaID = subject.SchemaID
+			}
+			subject.DatabaseKafkaSchemaRegistrySubject = registered
+			return &registered, okResponse, nil
+		}
+	}
+	f.schemaSubjects[dbUUID] = append(f.schemaSubjects[dbUUID], fakeSchemaSubject{
+		DatabaseKafkaSchemaRegistrySubject: registered,
+		compatibilityLevel:                 "BACKWARD",
+		registrations:                      1,
+	})
+
+	return &registered, okResponse, nil
+}
+
+// KafkaSchemaRegistrations returns how many times a schema was registered for
+// the subject, including registrations of the subject's latest schema.
+func (f *FakeDatabasesService) KafkaSchemaRegistrations(dbUUID, subjectName string) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, subject := range f.schemaSubjects[dbUUID] {
+		if subject.SubjectName == subjectName {
+			return subject.registrations
+		}
+	}
+	return 0
+}
+
+// DeleteKafkaSchemaRegistry ...
+func (f *FakeDatabasesService) DeleteKafkaSchemaRegistry(_ context.Context, dbUUID, subjectName string) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, subject := range f.schemaSubjects[dbUUID] {
+		if subject.SubjectName == subjectName {
+			f.schemaSubjects[dbUUID] = append(f.schemaSubjects[dbUUID][:i], f.schemaSubjects[dbUUID][i+1:]...)
+			return okResponse, nil
+		}
+	}
+
+	return notFoundResponse, errors.New("not found")
+}
+
+// GetKafkaSchemaRegistrySubjectConfig ...
+func (f *FakeDatabasesService) GetKafkaSchemaRegistrySubjectConfig(_ context.Context, dbUUID, subjectName string) (*godo.DatabaseKafkaSchemaRegistrySubjectConfigResponse, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, subject := range f.schemaSubjects[dbUUID] {
+		if subject.SubjectName == subjectName {
+			return &godo.DatabaseKafkaSchemaRegistrySubjectConfigResponse{
+				SubjectName:        subjectName,
+				CompatibilityLevel: subject.compatibilityLevel,
+			}, okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// UpdateKafkaSchemaRegistrySubjectConfig ...
+func (f *FakeDatabasesService) UpdateKafkaSchemaRegistrySubjectConfig(_ context.Context, dbUUID, subjectName string, req *godo.DatabaseKafkaSchemaRegistryConfig) (*godo.DatabaseKafkaSchemaRegistrySubjectConfigResponse, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.schemaSubjects[dbUUID] {
+		subject := &f.schemaSubjects[dbUUID][i]
+		if subject.SubjectName == subjectName {
+			subject.compatibilityLevel = req.CompatibilityLevel
+			return &godo.DatabaseKafkaSchemaRegistrySubjectConfigResponse{
+				SubjectName:        subjectName,
+				CompatibilityLevel: subject.compatibilityLevel,
+			}, okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// GetLogsink ...
+func (f *FakeDatabasesService) GetLogsink(_ context.Context, dbUUID, logsinkID string) (*godo.DatabaseLogsink, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, sink := range f.logsinks[dbUUID] {
+		if sink.ID == logsinkID {
+			return copyLogsink(sink), okResponse, nil
+		}
+	}
+
+	return nil, notFoundResponse, errors.New("not found")
+}
+
+// ListLogsinks ...
+func (f *FakeDatabasesService) ListLogsinks(_ context.Context, dbUUID string, _ *godo.ListOptions) ([]godo.DatabaseLogsink, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	sinks := make([]godo.DatabaseLogsink, 0, len(f.logsinks[dbUUID]))
+	for _, sink := range f.logsinks[dbUUID] {
+		sinks = append(sinks, *copyLogsink(sink))
+	}
+	return sinks, okResponse, nil
+}
+
+// CreateLogsink ...
+func (f *FakeDatabasesService) CreateLogsink(_ context.Context, dbUUID string, req *godo.DatabaseCreateLogsinkRequest) (*godo.DatabaseLogsink, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.logsinks == nil {
+		f.logsinks = make(map[string][]godo.DatabaseLogsink)
+	}
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	sink := godo.DatabaseLogsink{
+		ID:     uuid.New().String(),
+		Name:   req.Name,
+		Type:   req.Type,
+		Config: req.Config,
+	}
+	f.logsinks[dbUUID] = append(f.logsinks[dbUUID], *copyLogsink(sink))
+
+	return &sink, okResponse, nil
+}
+
+// UpdateLogsink ...
+func (f *FakeDatabasesService) UpdateLogsink(_ context.Context, dbUUID, logsinkID string, req *godo.DatabaseUpdateLogsinkRequest) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.logsinks[dbUUID] {
+		sink := &f.logsinks[dbUUID][i]
+		if sink.ID == logsinkID {
+			cfg := *req.Config
+			sink.Config = &cfg
+			return okResponse, nil
+		}
+	}
+
+	return notFoundResponse, errors.New("not found")
+}
+
+// DeleteLogsink ...
+func (f *FakeDatabasesService) DeleteLogsink(_ context.Context, dbUUID, logsinkID string) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, sink := range f.logsinks[dbUUID] {
+		if sink.ID == logsinkID {
+			f.logsinks[dbUUID] = append(f.logsinks[dbUUID][:i], f.logsinks[dbUUID][i+1:]...)
+			return okResponse, nil
+		}
+	}
+
+	return notFoundResponse, errors.New("not found")
+}
+
+func copyLogsink(sink godo.DatabaseLogsink) *godo.DatabaseLogsink {
+	if sink.Config != nil {
+		cfg := *sink.Config
+		sink.Config = &cfg
+	}
+	return &sink
+}
+
+// StartOnlineMigration ...
+func (f *FakeDatabasesService) StartOnlineMigration(_ context.Context, dbUUID string, _ *godo.DatabaseStartOnlineMigrationRequest) (*godo.DatabaseOnlineMigrationStatus, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.migrations == nil {
+		f.migrations = make(map[string]godo.DatabaseOnlineMigrationStatus)
+	}
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+	if current, ok := f.migrations[dbUUID]; ok && current.Status == "syncing" {
+		return nil, &godo.Response{Response: &http.Response{StatusCode: http.StatusConflict}}, errors.New("migration already in progress")
+	}
+
+	migration := godo.DatabaseOnlineMigrationStatus{
+		ID:        uuid.New().String(),
+		Status:    "syncing",
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	f.migrations[dbUUID] = migration
+
+	return &migration, okResponse, nil
+}
+
+// GetOnlineMigrationStatus ...
+func (f *FakeDatabasesService) GetOnlineMigrationStatus(_ context.Context, dbUUID string) (*godo.DatabaseOnlineMigrationStatus, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	migration, ok := f.migrations[dbUUID]
+	if !ok {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+	return &migration, okResponse, nil
+}
+
+// StopOnlineMigration ...
+func (f *FakeDatabasesService) StopOnlineMigration(_ context.Context, dbUUID, migrationID string) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	migration, ok := f.migrations[dbUUID]
+	if !ok || migration.ID != migrationID {
+		return notFoundResponse, errors.New("not found")
+	}
+	migration.Status = "canceled"
+	f.migrations[dbUUID] = migration
+
+	return okResponse, nil
+}
+
+// CreateIndex adds an index to a previously-created database. It isn't part
+// of godo.DatabasesService.
+func (f *FakeDatabasesService) CreateIndex(dbUUID string, index godo.DatabaseIndex) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.indexes == nil {
+		f.indexes = make(map[string][]godo.DatabaseIndex)
+	}
+
+	if !f.hasDatabase(dbUUID) {
+		return errors.New("not found")
+	}
+	f.indexes[dbUUID] = append(f.indexes[dbUUID], index)
+
+	return nil
+}
+
+// ListIndexes ...
+func (f *FakeDatabasesService) ListIndexes(_ context.Context, dbUUID string, _ *godo.ListOptions) ([]godo.DatabaseIndex, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	return append([]godo.DatabaseIndex(nil), f.indexes[dbUUID]...), okResponse, nil
+}
+
+// DeleteIndex ...
+func (f *FakeDatabasesService) DeleteIndex(_ context.Context, dbUUID, name string) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, index := range f.indexes[dbUUID] {
+		if index.IndexName == name {
+			f.indexes[dbUUID] = append(f.indexes[dbUUID][:i], f.indexes[dbUUID][i+1:]...)
+			return okResponse, nil
+		}
+	}
+
+	return notFoundResponse, errors.New("not found")
+}
+
+// CreateDatabaseEvent adds an event to a previously-created database. It isn't
+// part of godo.DatabasesService.
+func (f *FakeDatabasesService) CreateDatabaseEvent(dbUUID string, event godo.DatabaseEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.events == nil {
+		f.events = make(map[string][]godo.DatabaseEvent)
+	}
+
+	if !f.hasDatabase(dbUUID) {
+		return errors.New("not found")
+	}
+	f.events[dbUUID] = append(f.events[dbUUID], event)
+
+	return nil
+}
+
+// ListDatabaseEvents ...
+func (f *FakeDatabasesService) ListDatabaseEvents(_ context.Context, dbUUID string, _ *godo.ListOptions) ([]godo.DatabaseEvent, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	return append([]godo.DatabaseEvent(nil), f.events[dbUUID]...), okResponse, nil
+}
+
+// GetCA ...
+func (f *FakeDatabasesService) GetCA(_ context.Context, dbUUID string) (*godo.DatabaseCA, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	return &godo.DatabaseCA{Certificate: []byte("ca-" + dbUUID)}, okResponse, nil
+}
+
+// GetMetricsCredentials ...
+func (f *FakeDatabasesService) GetMetricsCredentials(_ context.Context) (*godo.DatabaseMetricsCredentials, *godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.metricsCreds == nil {
+		f.metricsCreds = &godo.DatabaseMetricsCredentials{
+			BasicAuthUsername: "metrics-user",
+			BasicAuthPassword: "metrics-password",
+		}
+	}
+
+	cpy := *f.metricsCreds
+	return &cpy, okResponse, nil
+}
+
+// UpdateMetricsCredentials ...
+func (f *FakeDatabasesService) UpdateMetricsCredentials(_ context.Context, req *godo.DatabaseUpdateMetricsCredentialsRequest) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cpy := *req.Credentials
+	f.metricsCreds = &cpy
+	return okResponse, nil
+}
+
+// UpdateMaintenance ...
+func (f *FakeDatabasesService) UpdateMaintenance(_ context.Context, dbUUID string, req *godo.DatabaseUpdateMaintenanceRequest) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.databases {
+		db := &f.databases[i]
+		if db.ID == dbUUID {
+			db.MaintenanceWindow = &godo.DatabaseMaintenanceWindow{
+				Day:  req.Day,
+				Hour: req.Hour + ":00",
+			}
+			return okResponse, nil
+		}
+	}
+
+	return notFoundResponse, errors.New("not found")
+}
+
+// GetFirewallRules ...
+func (f *FakeDatabasesService) GetFirewallRules(_ context.Context, dbUUID string) ([]godo.DatabaseFirewallRule, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	return append([]godo.DatabaseFirewallRule(nil), f.firewallRules[dbUUID]...), okResponse, nil
+}
+
+// UpdateFirewallRules ...
+func (f *FakeDatabasesService) UpdateFirewallRules(_ context.Context, dbUUID string, req *godo.DatabaseUpdateFirewallRulesRequest) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return notFoundResponse, errors.New("not found")
+	}
+
+	var rules []godo.DatabaseFirewallRule
+	for _, rule := range req.Rules {
+		rules = append(rules, godo.DatabaseFirewallRule{
+			ClusterUUID: dbUUID,
+			Type:        rule.Type,
+			Value:       rule.Value,
+		})
+	}
+	if f.firewallRules == nil {
+		f.firewallRules = make(map[string][]godo.DatabaseFirewallRule)
+	}
+	f.firewallRules[dbUUID] = rules
+	return okResponse, nil
+}
+
+// tagDatabase adds or removes a tag on a previously-created database.
+func (f *FakeDatabasesService) tagDatabase(dbUUID, tag string, add bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.databases {
+		db := &f.databases[i]
+		if db.ID != dbUUID {
+			continue
+		}
+		tags := slices.DeleteFunc(slices.Clone(db.Tags), func(t string) bool { return t == tag })
+		if add {
+			tags = append(tags, tag)
+		}
+		db.Tags = tags
+		return true
+	}
+	return false
+}
+
+// getConfig returns a database's engine config, which starts out empty.
+func getConfig[T any](f *FakeDatabasesService, dbUUID string) (*T, *godo.Response, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return nil, notFoundResponse, errors.New("not found")
+	}
+
+	var config T
+	b, err := json.Marshal(f.configs[dbUUID])
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, nil, err
+	}
+	return &config, okResponse, nil
+}
+
+// updateConfig sets the options that are set in config on a database.
+func updateConfig[T any](f *FakeDatabasesService, dbUUID string, config *T) (*godo.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.hasDatabase(dbUUID) {
+		return notFoundResponse, errors.New("not found")
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var options map[string]json.RawMessage
+	if err := json.Unmarshal(b, &options); err != nil {
+		return nil, err
+	}
+	if f.configs == nil {
+		f.configs = make(map[string]map[string]json.RawMessage)
+	}
+	if f.configs[dbUUID] == nil {
+		f.configs[dbUUID] = make(map[string]json.RawMessage)
+	}
+	for name, value := range options {
+		f.configs[dbUUID][name] = value
+	}
+	return okResponse, nil
+}
+
+// GetMySQLConfig ...
+func (f *FakeDatabasesService) GetMySQLConfig(_ context.Context, dbUUID string) (*godo.MySQLConfig, *godo.Response, error) {
+	return getConfig[godo.MySQLConfig](f, dbUUID)
+}
+
+// UpdateMySQLConfig ...
+func (f *FakeDatabasesService) UpdateMySQLConfig(_ context.Context, dbUUID string, config *godo.MySQLConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetPostgreSQLConfig ...
+func (f *FakeDatabasesService) GetPostgreSQLConfig(_ context.Context, dbUUID string) (*godo.PostgreSQLConfig, *godo.Response, error) {
+	return getConfig[godo.PostgreSQLConfig](f, dbUUID)
+}
+
+// UpdatePostgreSQLConfig ...
+func (f *FakeDatabasesService) UpdatePostgreSQLConfig(_ context.Context, dbUUID string, config *godo.PostgreSQLConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetRedisConfig ...
+func (f *FakeDatabasesService) GetRedisConfig(_ context.Context, dbUUID string) (*godo.RedisConfig, *godo.Response, error) {
+	return getConfig[godo.RedisConfig](f, dbUUID)
+}
+
+// UpdateRedisConfig ...
+func (f *FakeDatabasesService) UpdateRedisConfig(_ context.Context, dbUUID string, config *godo.RedisConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetValkeyConfig ...
+func (f *FakeDatabasesService) GetValkeyConfig(_ context.Context, dbUUID string) (*godo.ValkeyConfig, *godo.Response, error) {
+	return getConfig[godo.ValkeyConfig](f, dbUUID)
+}
+
+// UpdateValkeyConfig ...
+func (f *FakeDatabasesService) UpdateValkeyConfig(_ context.Context, dbUUID string, config *godo.ValkeyConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetMongoDBConfig ...
+func (f *FakeDatabasesService) GetMongoDBConfig(_ context.Context, dbUUID string) (*godo.MongoDBConfig, *godo.Response, error) {
+	return getConfig[godo.MongoDBConfig](f, dbUUID)
+}
+
+// UpdateMongoDBConfig ...
+func (f *FakeDatabasesService) UpdateMongoDBConfig(_ context.Context, dbUUID string, config *godo.MongoDBConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetKafkaConfig ...
+func (f *FakeDatabasesService) GetKafkaConfig(_ context.Context, dbUUID string) (*godo.KafkaConfig, *godo.Response, error) {
+	return getConfig[godo.KafkaConfig](f, dbUUID)
+}
+
+// UpdateKafkaConfig ...
+func (f *FakeDatabasesService) UpdateKafkaConfig(_ context.Context, dbUUID string, config *godo.KafkaConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
+
+// GetOpensearchConfig ...
+func (f *FakeDatabasesService) GetOpensearchConfig(_ context.Context, dbUUID string) (*godo.OpensearchConfig, *godo.Response, error) {
+	return getConfig[godo.OpensearchConfig](f, dbUUID)
+}
+
+// UpdateOpensearchConfig ...
+func (f *FakeDatabasesService) UpdateOpensearchConfig(_ context.Context, dbUUID string, config *godo.OpensearchConfig) (*godo.Response, error) {
+	return updateConfig(f, dbUUID, config)
+}
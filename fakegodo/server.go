@@ -34,7 +34,7 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		switch createReq.EngineSlug {
-		case "mysql", "pg", "mongodb", "redis":
+		case "mysql", "pg", "mongodb", "redis", "kafka":
 			rw.WriteHeader(http.StatusOK)
 			return
 		}
@@ -0,0 +1,41 @@
+package fakegodo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/digitalocean/godo"
+)
+
+// FakeTagsService is a fake godo TagsService that can tag and untag the
+// databases of a FakeDatabasesService. Tags don't need to be created first.
+type FakeTagsService struct {
+	Databases *FakeDatabasesService
+
+	// satisfy interface for unimplemented methods
+	godo.TagsService
+}
+
+// Create ...
+func (f *FakeTagsService) Create(_ context.Context, req *godo.TagCreateRequest) (*godo.Tag, *godo.Response, error) {
+	return &godo.Tag{Name: req.Name}, okResponse, nil
+}
+
+// TagResources ...
+func (f *FakeTagsService) TagResources(_ context.Context, tag string, req *godo.TagResourcesRequest) (*godo.Response, error) {
+	return f.tag(tag, req.Resources, true)
+}
+
+// UntagResources ...
+func (f *FakeTagsService) UntagResources(_ context.Context, tag string, req *godo.UntagResourcesRequest) (*godo.Response, error) {
+	return f.tag(tag, req.Resources, false)
+}
+
+func (f *FakeTagsService) tag(tag string, resources []godo.Resource, add bool) (*godo.Response, error) {
+	for _, resource := range resources {
+		if resource.Type != godo.DatabaseResourceType || !f.Databases.tagDatabase(resource.ID, tag, add) {
+			return notFoundResponse, errors.New("not found")
+		}
+	}
+	return okResponse, nil
+}
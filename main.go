@@ -17,29 +17,36 @@ limitations under the License.
 package main
 
 import (
-	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
-	"golang.org/x/oauth2"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/digitalocean/godo"
 
 	databasesv1alpha1 "github.com/digitalocean/do-operator/api/v1alpha1"
+	databasesv1beta1 "github.com/digitalocean/do-operator/api/v1beta1"
 	"github.com/digitalocean/do-operator/api/webhooks"
+	"github.com/digitalocean/do-operator/apitoken"
 	"github.com/digitalocean/do-operator/controllers"
+	"github.com/digitalocean/do-operator/extgodo"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -53,6 +60,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(databasesv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(databasesv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -62,15 +70,33 @@ func main() {
 		enableLeaderElection bool
 		probeAddr            string
 		doAPIToken           string
+		doAPITokenFile       string
+		doAPITokenSecret     string
+		doAPITokenSecretKey  string
 		doAPIURL             string
+		watchNamespaces      string
+		driftPolicy          string
+		defaultRegion        string
 	)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.StringVar(&doAPIToken, "do-api-token", "", "DigitalOcean public API token for managing resources.")
+	flag.StringVar(&doAPIToken, "do-api-token", "", "DigitalOcean public API token for managing resources. "+
+		"Prefer --do-api-token-file or --do-api-token-secret, which keep the token out of the process arguments and allow rotating it without a restart.")
+	flag.StringVar(&doAPITokenFile, "do-api-token-file", "", "Path to a file containing the DigitalOcean API token, such as a mounted Secret. "+
+		"The file is re-read periodically.")
+	flag.StringVar(&doAPITokenSecret, "do-api-token-secret", "", "Secret containing the DigitalOcean API token, as <namespace>/<name>. "+
+		"The token is reloaded whenever the Secret changes.")
+	flag.StringVar(&doAPITokenSecretKey, "do-api-token-secret-key", "access-token", "Key of the DigitalOcean API token in the --do-api-token-secret Secret.")
 	flag.StringVar(&doAPIURL, "do-api-url", "https://api.digitalocean.com", "Base URL of the DigitalOcean API.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma-separated list of namespaces to watch. "+
+		"If set, the operator only manages objects in these namespaces and only needs access to them. Defaults to all namespaces.")
+	flag.StringVar(&driftPolicy, "drift-policy", databasesv1alpha1.DriftPolicyObserve, "What to do when a database cluster's settings no longer match its spec, for clusters that don't set spec.driftPolicy: "+
+		"Observe only reports the drift in the cluster's status, while Enforce also changes the cluster back.")
+	flag.StringVar(&defaultRegion, "default-region", "", "Region slug for database clusters that don't set spec.region, such as nyc3. "+
+		"If unset, database clusters must set a region.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -83,8 +109,55 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)).WithValues("version", version))
 
+	tokenFlagsSet := 0
+	for _, f := range []string{doAPIToken, doAPITokenFile, doAPITokenSecret} {
+		if f != "" {
+			tokenFlagsSet++
+		}
+	}
+	if tokenFlagsSet > 1 {
+		setupLog.Error(fmt.Errorf("only one of --do-api-token, --do-api-token-file and --do-api-token-secret may be set"), "invalid flags")
+		os.Exit(1)
+	}
+	if driftPolicy != databasesv1alpha1.DriftPolicyObserve && driftPolicy != databasesv1alpha1.DriftPolicyEnforce {
+		setupLog.Error(fmt.Errorf("--drift-policy must be %s or %s, got %q", databasesv1alpha1.DriftPolicyObserve, databasesv1alpha1.DriftPolicyEnforce, driftPolicy), "invalid flags")
+		os.Exit(1)
+	}
+	var tokenSecretKey types.NamespacedName
+	if doAPITokenSecret != "" {
+		namespace, name, ok := strings.Cut(doAPITokenSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(fmt.Errorf("--do-api-token-secret must be <namespace>/<name>, got %q", doAPITokenSecret), "invalid flags")
+			os.Exit(1)
+		}
+		tokenSecretKey = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	var (
+		namespaces      []string
+		cacheNamespaces map[string]cache.Config
+	)
+	for _, ns := range strings.Split(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	if len(namespaces) > 0 {
+		cacheNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			cacheNamespaces[ns] = cache.Config{}
+		}
+	}
+	// The webhooks look up objects through the manager's cache, so they can
+	// only validate objects in the namespaces it's restricted to.
+	webhooks.SetWatchNamespaces(namespaces)
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
+		Cache: cache.Options{
+			DefaultNamespaces: cacheNamespaces,
+			ByObject:          controllers.GeneratedObjectsOnly(),
+		},
 		WebhookServer: &webhook.DefaultServer{
 			Options: webhook.Options{
 				Port: 9443,
@@ -113,62 +186,170 @@ func main() {
 		os.Exit(1)
 	}
 
-	godoClient, err := makeGodo(context.Background(), doAPIToken, doAPIURL)
+	tokenSource := apitoken.NewSource(doAPIToken)
+	switch {
+	case doAPITokenFile != "":
+		loader := &apitoken.FileLoader{
+			Source:   tokenSource,
+			Path:     doAPITokenFile,
+			Interval: 30 * time.Second,
+		}
+		if err := loader.Load(); err != nil {
+			// Keep running, but unready, until the file shows up.
+			setupLog.Error(err, "unable to read API token file", "path", doAPITokenFile)
+		}
+		if err := mgr.Add(loader); err != nil {
+			setupLog.Error(err, "unable to set up API token file loader")
+			os.Exit(1)
+		}
+	case doAPITokenSecret != "":
+		if err = (&controllers.APITokenSecretReconciler{
+			SecretKey: tokenSecretKey,
+			DataKey:   doAPITokenSecretKey,
+			Source:    tokenSource,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "APITokenSecret")
+			os.Exit(1)
+		}
+	}
+
+	godoClient, err := makeGodo(tokenSource, doAPIURL)
 	if err != nil {
 		setupLog.Error(err, "unable to create godo client")
 		os.Exit(1)
 	}
+	// Secrets and ConfigMaps that the operator doesn't generate are watched
+	// through their metadata.
+	metadata, err := controllers.AddMetadataCache(mgr, namespaces)
+	if err != nil {
+		setupLog.Error(err, "unable to create metadata cache")
+		os.Exit(1)
+	}
+	// Objects that use a DigitalOceanProviderConfig get a client for the
+	// config's token instead of the operator's own.
+	godoClients := apitoken.NewClientCache(mgr.GetClient(), metadata, mgr.GetAPIReader(), godoClient, func(source *apitoken.Source) (*godo.Client, error) {
+		return makeGodo(source, doAPIURL)
+	})
 
+	metricsRotations := controllers.NewMetricsCredentialsRotations()
 	if err = (&controllers.DatabaseClusterReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		GodoClient: godoClient,
+		Client:           mgr.GetClient(),
+		APIReader:        mgr.GetAPIReader(),
+		Scheme:           mgr.GetScheme(),
+		GodoClients:      godoClients,
+		Recorder:         mgr.GetEventRecorder("databasecluster-controller"),
+		DriftPolicy:      driftPolicy,
+		MetricsRotations: metricsRotations,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DatabaseCluster")
 		os.Exit(1)
 	}
 	if err = (&controllers.DatabaseClusterReferenceReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		GodoClient: godoClient,
+		Client:           mgr.GetClient(),
+		APIReader:        mgr.GetAPIReader(),
+		Scheme:           mgr.GetScheme(),
+		GodoClients:      godoClients,
+		Recorder:         mgr.GetEventRecorder("databaseclusterreference-controller"),
+		MetricsRotations: metricsRotations,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DatabaseClusterReference")
 		os.Exit(1)
 	}
-	if err = webhooks.SetupDatabaseClusterReferenceWebhookWithManager(mgr, godoClient); err != nil {
+	if err = webhooks.SetupDatabaseClusterReferenceWebhookWithManager(mgr, godoClients); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseClusterReference")
 		os.Exit(1)
 	}
 	if err = (&controllers.DatabaseUserReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		GodoClient: godoClient,
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    mgr.GetEventRecorder("databaseuser-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DatabaseUser")
 		os.Exit(1)
 	}
-	if err = webhooks.SetupDatabaseUserWebhookWithManager(mgr, godoClient); err != nil {
+	if err = webhooks.SetupDatabaseUserWebhookWithManager(mgr, godoClients); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseUser")
 		os.Exit(1)
 	}
 	if err = (&controllers.DatabaseUserReferenceReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		GodoClient: godoClient,
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DatabaseUserReference")
 		os.Exit(1)
 	}
-	if err = webhooks.SetupDatabaseUserReferenceWebhookWithManager(mgr, godoClient); err != nil {
+	if err = webhooks.SetupDatabaseUserReferenceWebhookWithManager(mgr, godoClients); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseUserReference")
 		os.Exit(1)
 	}
-	if err = webhooks.SetupDatabaseClusterWebhookWithManager(mgr, godoClient); err != nil {
+	if err = webhooks.SetupDatabaseClusterWebhookWithManager(mgr, godoClients, defaultRegion, driftPolicy); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseCluster")
 		os.Exit(1)
 	}
+	if err = (&controllers.KafkaSchemaSubjectReconciler{
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    mgr.GetEventRecorder("kafkaschemasubject-controller"),
+		Metadata:    metadata,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafkaSchemaSubject")
+		os.Exit(1)
+	}
+	if err = webhooks.SetupKafkaSchemaSubjectWebhookWithManager(mgr, godoClients); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KafkaSchemaSubject")
+		os.Exit(1)
+	}
+	if err = (&controllers.DatabaseLogsinkReconciler{
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    mgr.GetEventRecorder("databaselogsink-controller"),
+		Metadata:    metadata,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseLogsink")
+		os.Exit(1)
+	}
+	if err = webhooks.SetupDatabaseLogsinkWebhookWithManager(mgr, godoClients); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseLogsink")
+		os.Exit(1)
+	}
+	if err = (&controllers.DatabaseOnlineMigrationReconciler{
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
+		Recorder:    mgr.GetEventRecorder("databaseonlinemigration-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseOnlineMigration")
+		os.Exit(1)
+	}
+	if err = webhooks.SetupDatabaseOnlineMigrationWebhookWithManager(mgr, godoClients); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseOnlineMigration")
+		os.Exit(1)
+	}
+	if err = (&controllers.OpenSearchIndexPolicyReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		GodoClients: godoClients,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenSearchIndexPolicy")
+		os.Exit(1)
+	}
+	if err = webhooks.SetupOpenSearchIndexPolicyWebhookWithManager(mgr, godoClients); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenSearchIndexPolicy")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
+	metrics.Registry.MustRegister(controllers.NewObjectsCollector(mgr.GetClient()))
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -177,6 +358,14 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// Without an operator token, every object must use a
+	// DigitalOceanProviderConfig, so there's no token to check.
+	if tokenFlagsSet > 0 {
+		if err := mgr.AddReadyzCheck("do-api-token", tokenSource.Check); err != nil {
+			setupLog.Error(err, "unable to set up API token ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.WithValues("version", version).Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -185,7 +374,12 @@ func main() {
 	}
 }
 
-func makeGodo(ctx context.Context, token, addr string) (*godo.Client, error) {
-	client := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
-	return godo.New(client, godo.SetBaseURL(addr), godo.SetUserAgent("do-operator/"+version))
+func makeGodo(tokenSource *apitoken.Source, addr string) (*godo.Client, error) {
+	httpClient := tokenSource.HTTPClient()
+	// Rate limits apply per token, so every client gets its own limiter, shared
+	// by all of the reconcilers and webhooks using the client.
+	// Requests are instrumented below the limiter so that the metrics count
+	// every attempt sent to the API, including retries.
+	httpClient.Transport = extgodo.NewRateLimiter().Transport(extgodo.InstrumentTransport(httpClient.Transport))
+	return godo.New(httpClient, godo.SetBaseURL(addr), godo.SetUserAgent("do-operator/"+version))
 }